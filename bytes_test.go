@@ -42,3 +42,52 @@ func TestRawBytes_IsZeroBencodeValue(t *testing.T) {
 	b = bencode.RawBytes("x")
 	require.False(t, b.IsZeroBencodeValue())
 }
+
+type pieceHash []byte
+
+type octet uint8
+
+type flag uint8
+
+func (f flag) MarshalBencode() ([]byte, error) {
+	if f != 0 {
+		return []byte("i1e"), nil
+	}
+	return []byte("i0e"), nil
+}
+
+func (f *flag) UnmarshalBencode(b []byte) error {
+	*f = flag(b[1] - '0')
+	return nil
+}
+
+func TestNamedBytesSlice(t *testing.T) {
+	var v struct {
+		Hash   pieceHash `bencode:"hash"`
+		Octets []octet   `bencode:"octets"`
+	}
+
+	raw := "d4:hash3:abc6:octets2:xye"
+
+	require.NoError(t, bencode.Unmarshal([]byte(raw), &v))
+	require.Equal(t, pieceHash("abc"), v.Hash)
+	require.Equal(t, []octet{'x', 'y'}, v.Octets)
+
+	actual, err := bencode.Marshal(v)
+	require.NoError(t, err)
+	test.StringEqual(t, raw, actual)
+
+	actual, err = bencode.Marshal(pieceHash("hello"))
+	require.NoError(t, err)
+	test.StringEqual(t, "5:hello", actual)
+}
+
+func TestBytesSlice_element_marshaler(t *testing.T) {
+	actual, err := bencode.Marshal([]flag{1, 0})
+	require.NoError(t, err)
+	test.StringEqual(t, "li1ei0ee", actual)
+
+	var v []flag
+	require.NoError(t, bencode.Unmarshal([]byte("li1ei0ee"), &v))
+	require.Equal(t, []flag{1, 0}, v)
+}
@@ -0,0 +1,40 @@
+package bencode
+
+import (
+	"github.com/trim21/go-bencode/internal/decoder"
+	"github.com/trim21/go-bencode/internal/errors"
+)
+
+// DuplicateKeyPolicy decides what [CanonicalizeOptions.Canonicalize] does with duplicate dictionary keys.
+type DuplicateKeyPolicy = decoder.DuplicateKeyPolicy
+
+const (
+	DuplicateKeyLastWins  = decoder.DuplicateKeyLastWins  // keep the last value, like UnmarshalRelaxed
+	DuplicateKeyFirstWins = decoder.DuplicateKeyFirstWins // keep the first value
+	DuplicateKeyError     = decoder.DuplicateKeyError     // return an error
+)
+
+// CanonicalizeOptions configures [CanonicalizeOptions.Canonicalize].
+type CanonicalizeOptions struct {
+	// Duplicates decides which value is kept when a dictionary has duplicate keys.
+	// Default to DuplicateKeyLastWins.
+	Duplicates DuplicateKeyPolicy
+}
+
+// Canonicalize turns input accepted by [UnmarshalRelaxed] into canonical bencode:
+// dictionary keys are sorted bytewise and duplicate keys are resolved by o.Duplicates.
+//
+// Strings and integers are copied as-is, so binary strings and integers
+// of any size round-trip exactly.
+func (o CanonicalizeOptions) Canonicalize(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, errors.ErrEmptyData()
+	}
+
+	return decoder.Canonicalize(data, o.Duplicates)
+}
+
+// Canonicalize is CanonicalizeOptions.Canonicalize with default options.
+func Canonicalize(data []byte) ([]byte, error) {
+	return CanonicalizeOptions{}.Canonicalize(data)
+}
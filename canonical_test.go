@@ -0,0 +1,68 @@
+package bencode_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trim21/go-bencode"
+	"github.com/trim21/go-bencode/internal/test"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		expected string
+	}{
+		{name: "canonical", raw: "d1:ai1e1:bi2ee", expected: "d1:ai1e1:bi2ee"},
+		{name: "unordered", raw: "d1:bi2e1:ai1ee", expected: "d1:ai1e1:bi2ee"},
+		{name: "nested", raw: "ld1:zd1:yi1e1:xi2ee1:a0:ee", expected: "ld1:a0:1:zd1:xi2e1:yi1eeee"},
+		{name: "duplicate keys", raw: "d1:bi1e1:ai1e1:bi2ee", expected: "d1:ai1e1:bi2ee"},
+		{name: "bytewise order", raw: "d1:\xff0:1:a0:2:\x00\x000:e", expected: "d2:\x00\x000:1:a0:1:\xff0:e"},
+		{name: "big int", raw: "d1:bi123456789012345678901234567890e1:ai-1ee", expected: "d1:ai-1e1:bi123456789012345678901234567890ee"},
+		{name: "binary string", raw: "d1:b3:\x00\xff\x801:a0:e", expected: "d1:a0:1:b3:\x00\xff\x80e"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := bencode.Canonicalize([]byte(tt.raw))
+			require.NoError(t, err)
+			test.StringEqual(t, tt.expected, actual)
+			require.True(t, bencode.Valid(actual))
+		})
+	}
+}
+
+func TestCanonicalize_duplicate_policy(t *testing.T) {
+	raw := []byte("d1:ai1e1:bi0e1:ai2e1:ai3ee")
+
+	actual, err := bencode.CanonicalizeOptions{Duplicates: bencode.DuplicateKeyLastWins}.Canonicalize(raw)
+	require.NoError(t, err)
+	test.StringEqual(t, "d1:ai3e1:bi0ee", actual)
+
+	actual, err = bencode.CanonicalizeOptions{Duplicates: bencode.DuplicateKeyFirstWins}.Canonicalize(raw)
+	require.NoError(t, err)
+	test.StringEqual(t, "d1:ai1e1:bi0ee", actual)
+
+	_, err = bencode.CanonicalizeOptions{Duplicates: bencode.DuplicateKeyError}.Canonicalize(raw)
+	require.Error(t, err)
+}
+
+func TestCanonicalize_does_not_alias_input(t *testing.T) {
+	raw := []byte("i1e")
+	actual, err := bencode.Canonicalize(raw)
+	require.NoError(t, err)
+
+	raw[1] = '2'
+	require.Equal(t, "i1e", string(actual))
+}
+
+func TestCanonicalize_invalid(t *testing.T) {
+	for _, raw := range []string{"", "i01e", "i-0e", "d1:bi1e1:ai1e", "d1:bi1e1:a", "di1ei2ee", "d1:bi1e1:ai1eei1e", "x", "l1:a", "d1:b1:a1:a05:helloe"} {
+		t.Run(raw, func(t *testing.T) {
+			_, err := bencode.Canonicalize([]byte(raw))
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package bencode
+
+import (
+	"github.com/trim21/go-bencode/internal/errors"
+)
+
+// A SyntaxError describes malformed bencode input, Offset is where the error occurred.
+type SyntaxError = errors.SyntaxError
+
+// An UnmarshalTypeError describes a bencode value that is not appropriate for a Go type.
+type UnmarshalTypeError = errors.UnmarshalTypeError
+
+// An InvalidUnmarshalError describes an invalid argument passed to Unmarshal.
+// (The argument to Unmarshal must be a non-nil pointer.)
+type InvalidUnmarshalError = errors.InvalidUnmarshalError
+
+// A LimitError is returned when input exceeds one of the [Limits], or the default max depth.
+type LimitError = errors.LimitError
+
+// An UnknownFieldError describes a dictionary key without matching struct field,
+// see [UnmarshalOptions.DisallowUnknownFields].
+type UnknownFieldError = errors.UnknownFieldError
+
+// A MissingFieldsError lists keys of `required` struct fields missing from a dictionary.
+type MissingFieldsError = errors.MissingFieldsError
+
+// UnmarshalErrors is returned by a [Decoder] with [Decoder.SetCollectErrors],
+// Errors are [*UnmarshalTypeError], [*UnmarshalerError], [*UnknownFieldError]
+// or [*MissingFieldsError] in input order.
+type UnmarshalErrors = errors.UnmarshalErrors
+
+// An UnsupportedTypeError is returned when a Go type can't be encoded or decoded.
+type UnsupportedTypeError = errors.UnsupportedTypeError
+
+// A MarshalError describes a Go value that can't be encoded, with the path to it
+// from the top-level value. It wraps the cause, like [ErrNilPtr] or an error returned by MarshalBencode.
+type MarshalError = errors.MarshalError
+
+// An UnmarshalerError wraps an error returned by UnmarshalBencode method.
+type UnmarshalerError = errors.UnmarshalerError
+
+// ErrorSnippet renders a hex/ASCII dump of data around the offset carried by err,
+// with the failing byte marked. It returns an empty string if err doesn't carry an offset.
+//
+//	00000000  64 31 3a 61 69 31 65 31  3a 62 78 65              |d1:ai1e1:bxe|
+//	                                         ^^
+func ErrorSnippet(data []byte, err error) string {
+	offset, ok := errors.Offset(err)
+	if !ok {
+		return ""
+	}
+
+	return errors.Snippet(data, offset)
+}
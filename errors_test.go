@@ -0,0 +1,233 @@
+package bencode_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trim21/go-bencode"
+)
+
+func TestUnmarshal_syntax_error(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		offset int
+	}{
+		{name: "empty", raw: "", offset: 0},
+		{name: "unordered keys", raw: "d1:bi1e1:ai2ee", offset: 7},
+		{name: "duplicated keys", raw: "d1:ai1e1:ai2ee", offset: 7},
+		{name: "string missing colon", raw: "d1:a5e", offset: 4},
+		{name: "string leading zero", raw: "d1:a01:xe", offset: 4},
+		{name: "unexpected end", raw: "d1:ai1e", offset: 7},
+		{name: "trailing data", raw: "i1ei2e", offset: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v any
+			err := bencode.Unmarshal([]byte(tt.raw), &v)
+
+			var syntaxError *bencode.SyntaxError
+			require.ErrorAs(t, err, &syntaxError)
+			require.Equal(t, tt.offset, syntaxError.Offset)
+		})
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		var v struct {
+			A int `bencode:"a"`
+			B int `bencode:"b"`
+		}
+
+		var syntaxError *bencode.SyntaxError
+		require.ErrorAs(t, bencode.Unmarshal([]byte("d1:bi1e1:ai2ee"), &v), &syntaxError)
+		require.Equal(t, 7, syntaxError.Offset)
+	})
+}
+
+func TestUnmarshal_type_error(t *testing.T) {
+	t.Run("overflow", func(t *testing.T) {
+		var v int8
+		var typeError *bencode.UnmarshalTypeError
+		require.ErrorAs(t, bencode.Unmarshal([]byte("i300e"), &v), &typeError)
+		require.Equal(t, reflect.TypeFor[int8](), typeError.Type)
+		require.Equal(t, "number 300", typeError.Value)
+	})
+
+	t.Run("negative uint", func(t *testing.T) {
+		var v uint
+		var typeError *bencode.UnmarshalTypeError
+		require.ErrorAs(t, bencode.Unmarshal([]byte("i-1e"), &v), &typeError)
+		require.Equal(t, reflect.TypeFor[uint](), typeError.Type)
+	})
+
+	t.Run("int64 overflow", func(t *testing.T) {
+		var v int64
+		var typeError *bencode.UnmarshalTypeError
+		require.ErrorAs(t, bencode.Unmarshal([]byte("i9223372036854775808e"), &v), &typeError)
+	})
+
+	t.Run("byte array length", func(t *testing.T) {
+		var v [4]byte
+		var typeError *bencode.UnmarshalTypeError
+		require.ErrorAs(t, bencode.Unmarshal([]byte("3:abc"), &v), &typeError)
+		require.Equal(t, "string of length 3", typeError.Value)
+	})
+
+	t.Run("array length", func(t *testing.T) {
+		var v [2]int
+		var typeError *bencode.UnmarshalTypeError
+		require.ErrorAs(t, bencode.Unmarshal([]byte("li1ee"), &v), &typeError)
+		require.ErrorAs(t, bencode.Unmarshal([]byte("li1ei2ei3ee"), &v), &typeError)
+	})
+}
+
+func TestUnmarshal_unsupported_type_error(t *testing.T) {
+	var v map[int]string
+	var unsupported *bencode.UnsupportedTypeError
+	require.ErrorAs(t, bencode.Unmarshal([]byte("de"), &v), &unsupported)
+	require.Equal(t, reflect.TypeFor[int](), unsupported.Type)
+
+	var invalid *bencode.InvalidUnmarshalError
+	require.ErrorAs(t, bencode.Unmarshal([]byte("de"), v), &invalid)
+}
+
+var errCustom = errors.New("custom error")
+
+type failingUnmarshaler struct{}
+
+func (failingUnmarshaler) UnmarshalBencode([]byte) error {
+	return errCustom
+}
+
+func TestUnmarshal_unmarshaler_error_type(t *testing.T) {
+	var v struct {
+		A failingUnmarshaler `bencode:"a"`
+	}
+
+	err := bencode.Unmarshal([]byte("d1:ai1ee"), &v)
+
+	var unmarshalerError *bencode.UnmarshalerError
+	require.ErrorAs(t, err, &unmarshalerError)
+	require.Equal(t, 4, unmarshalerError.Offset)
+	require.ErrorIs(t, err, errCustom)
+}
+
+func TestErrorSnippet(t *testing.T) {
+	data := []byte("d1:ai1e1:bxe")
+
+	var v map[string]any
+	err := bencode.Unmarshal(data, &v)
+	require.Error(t, err)
+
+	expected := "" +
+		"00000000  64 31 3a 61 69 31 65 31  3a 62 78 65              |d1:ai1e1:bxe|\n" +
+		"                                         ^^\n"
+
+	require.Equal(t, expected, bencode.ErrorSnippet(data, err))
+
+	require.Empty(t, bencode.ErrorSnippet(data, errCustom))
+}
+
+func TestErrorSnippet_long_input(t *testing.T) {
+	data := []byte("d4:infod6:lengthi1e4:name3:abc12:piece lengthi1e6:pieces0:e8:announcexe")
+
+	var v map[string]any
+	err := bencode.UnmarshalRelaxed(data, &v)
+	require.Error(t, err)
+
+	offset := len(data) - 2
+
+	var syntaxError *bencode.SyntaxError
+	require.ErrorAs(t, err, &syntaxError)
+	require.Equal(t, offset, syntaxError.Offset)
+
+	snippet := bencode.ErrorSnippet(data, err)
+	require.Contains(t, snippet, "00000040  ")
+	require.Contains(t, snippet, "00000030  ")
+	require.NotContains(t, snippet, "00000000  ")
+}
+
+func TestUnmarshal_error_path(t *testing.T) {
+	type File struct {
+		Length int8     `bencode:"length"`
+		Path   []string `bencode:"path"`
+	}
+
+	type Info struct {
+		Files []File `bencode:"files"`
+	}
+
+	type Torrent struct {
+		Info Info `bencode:"info"`
+	}
+
+	t.Run("type error", func(t *testing.T) {
+		raw := "d4:infod5:filesld6:lengthi1e4:pathl1:aeed6:lengthi300e4:pathl1:beeeee"
+
+		var v Torrent
+		err := bencode.Unmarshal([]byte(raw), &v)
+
+		var typeError *bencode.UnmarshalTypeError
+		require.ErrorAs(t, err, &typeError)
+		require.Equal(t, "info.files[1].length", typeError.Path.String())
+		require.Contains(t, err.Error(), "info.files[1].length")
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		raw := "d4:infod5:filesld6:lengthi01eeeee"
+
+		var v Torrent
+		err := bencode.Unmarshal([]byte(raw), &v)
+
+		var syntaxError *bencode.SyntaxError
+		require.ErrorAs(t, err, &syntaxError)
+		require.Equal(t, bencode.Path{
+			bencode.PathElement{Key: "info"},
+			bencode.PathElement{Key: "files"},
+			bencode.PathElement{Index: 0, IsIndex: true},
+			bencode.PathElement{Key: "length"},
+		}, syntaxError.Path)
+	})
+
+	t.Run("map and any", func(t *testing.T) {
+		var m map[string][]any
+		err := bencode.Unmarshal([]byte("d1:ali1eld1:xi01eeeee"), &m)
+
+		var syntaxError *bencode.SyntaxError
+		require.ErrorAs(t, err, &syntaxError)
+		require.Equal(t, "a[1][0].x", syntaxError.Path.String())
+	})
+
+	t.Run("deep", func(t *testing.T) {
+		const depth = 9000
+		raw := "d1:a" + strings.Repeat("l", depth) + "i01e" + strings.Repeat("e", depth) + "e"
+
+		var v any
+		err := bencode.Unmarshal([]byte(raw), &v)
+
+		var syntaxError *bencode.SyntaxError
+		require.ErrorAs(t, err, &syntaxError)
+		require.Equal(t, "a"+strings.Repeat("[0]", depth), syntaxError.Path.String())
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		var v Info
+		err := bencode.Unmarshal([]byte("d5:otherli1ei-0eee"), &v)
+
+		var syntaxError *bencode.SyntaxError
+		require.ErrorAs(t, err, &syntaxError)
+		require.Equal(t, "other[1]", syntaxError.Path.String())
+	})
+
+	t.Run("top-level", func(t *testing.T) {
+		var v int8
+		var typeError *bencode.UnmarshalTypeError
+		require.ErrorAs(t, bencode.Unmarshal([]byte("i300e"), &v), &typeError)
+		require.Empty(t, typeError.Path)
+	})
+}
@@ -36,7 +36,7 @@ func ExampleMarshal() {
 	}
 
 	fmt.Println(string(b))
-	// Output: d3:objd37:a long string name replace field name3:vvv1:vi2ee5:usersld2:idi1e4:name3:saied2:idi2e4:name6:trim21eee
+	// Output: d3:objd37:a long string name replace field name3:vvv1:vi2ee5:usersld2:id1:14:name3:saied2:id1:24:name6:trim21eee
 }
 
 func ExampleUnmarshal() {
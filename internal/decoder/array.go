@@ -35,16 +35,16 @@ func (d *arrayDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect.
 	bufSize := len(buf)
 
 	depth++
-	if depth > maxDecodeNestingDepth {
-		return 0, errors.ErrExceededMaxDepth(buf[cursor], cursor)
+	if err := ctx.checkDepth(depth, cursor); err != nil {
+		return 0, err
 	}
 
 	if cursor >= bufSize {
-		return 0, errors.DataTooShort()
+		return 0, errors.DataTooShort(cursor)
 	}
 
 	if buf[cursor] != 'l' {
-		return 0, errors.ErrExpecting("list", buf, cursor)
+		return 0, errUnexpectedKind(buf, cursor, rv.Type())
 	}
 
 	cursor++
@@ -53,22 +53,22 @@ func (d *arrayDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect.
 
 	for {
 		if cursor >= bufSize {
-			return 0, errors.DataTooShort()
+			return 0, errors.DataTooShort(cursor)
 		}
 
 		if buf[cursor] == 'e' {
 			if index != d.alen {
-				return 0, fmt.Errorf("bencode: failed to decode list into GO array, bencode list length %d. array length %d", index, d.alen)
+				return 0, errors.ErrTypeMismatch(fmt.Sprintf("bencode: failed to decode list into GO array, bencode list length %d. array length %d", index, d.alen), d.aType, cursor)
 			}
 
 			return cursor + 1, nil
 		}
 
 		if index >= d.alen {
-			return 0, fmt.Errorf("array overflow when decoding list: index %d", cursor)
+			return 0, errors.ErrTypeMismatch(fmt.Sprintf("bencode: array overflow when decoding list, array length %d", d.alen), d.aType, cursor)
 		}
 
-		c, err := d.valueDecoder.Decode(ctx, cursor, depth, rv.Index(index))
+		c, err := decodeElement(ctx, d.valueDecoder, cursor, depth, rv.Index(index), indexElement(index))
 		if err != nil {
 			return 0, err
 		}
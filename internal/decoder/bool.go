@@ -17,33 +17,25 @@ func newBoolDecoder(structName, fieldName string) *boolDecoder {
 
 func (d *boolDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect.Value) (int, error) {
 	buf := ctx.Buf
-	if cursor > len(buf)-3 {
-		return 0, errors.ErrSyntax("invalid int", cursor)
+	if cursor < len(buf) && buf[cursor] != 'i' {
+		return 0, errUnexpectedKind(buf, cursor, rv.Type())
 	}
 
-	switch buf[cursor] {
-	case 'i':
-		// i0e;
-		// i1e;
-
-		cursor++
-		switch buf[cursor] {
-		case '0':
-			rv.SetBool(false)
-		case '1':
-			rv.SetBool(true)
-		default:
-			return 0, errors.ErrInvalidCharacter(buf[cursor], "bool value", cursor)
-		}
-
-		cursor++
-		if buf[cursor] != 'e' {
-			return 0, errors.ErrUnexpectedEnd("'e' end bool value", cursor)
-		}
-
-		cursor++
-		return cursor, nil
+	// i0e;
+	// i1e;
+	b, end, err := ctx.decodeInteger(cursor)
+	if err != nil {
+		return 0, err
 	}
 
-	return 0, errors.ErrUnexpectedEnd("bool", cursor)
+	switch string(b) {
+	case "0":
+		rv.SetBool(false)
+	case "1":
+		rv.SetBool(true)
+	default:
+		return 0, errors.ErrValueOverflow(b, rv.Type(), cursor)
+	}
+
+	return end, nil
 }
@@ -4,12 +4,18 @@ import (
 	"fmt"
 	"reflect"
 	"slices"
-)
 
-var (
-	bytesType = reflect.TypeFor[[]byte]()
+	"github.com/trim21/go-bencode/internal/errors"
 )
 
+// isBytesSlice reports whether rt is decoded from bencode string,
+// it's true for any slice of uint8 kind, including named types like `type InfoHash []byte`,
+// unless the element type implements Unmarshaler.
+func isBytesSlice(rt reflect.Type) bool {
+	return rt.Kind() == reflect.Slice && rt.Elem().Kind() == reflect.Uint8 &&
+		!reflect.PointerTo(rt.Elem()).Implements(unmarshalerType)
+}
+
 type bytesSliceDecoder struct {
 	rt         reflect.Type
 	structName string
@@ -25,11 +31,19 @@ func newByteSliceDecoder(rt reflect.Type, structName string, fieldName string) D
 }
 
 func (d *bytesSliceDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect.Value) (int, error) {
-	bytes, c, err := readString(ctx.Buf, cursor)
+	if !isStringStart(ctx.Buf[cursor]) {
+		return 0, errUnexpectedKind(ctx.Buf, cursor, d.rt)
+	}
+
+	bytes, c, err := ctx.readString(cursor)
 	if err != nil {
 		return 0, err
 	}
 
+	if err := ctx.checkString(len(bytes), cursor); err != nil {
+		return 0, err
+	}
+
 	rv.SetBytes(slices.Clone(bytes))
 	return c, nil
 }
@@ -51,13 +65,23 @@ type bytesArrayDecoder struct {
 }
 
 func (a *bytesArrayDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect.Value) (int, error) {
-	bytes, end, err := readString(ctx.Buf, cursor)
+	if !isStringStart(ctx.Buf[cursor]) {
+		return 0, errUnexpectedKind(ctx.Buf, cursor, a.rt)
+	}
+
+	bytes, end, err := ctx.readString(cursor)
 	if err != nil {
 		return 0, err
 	}
 
 	if len(bytes) != a.size {
-		return 0, fmt.Errorf("bencode: string length mismatch expected size: expecting %d, actuall %d. index %d", a.size, len(bytes), cursor)
+		return 0, &errors.UnmarshalTypeError{
+			Value:  fmt.Sprintf("string of length %d", len(bytes)),
+			Type:   a.rt,
+			Offset: cursor,
+			Struct: a.structName,
+			Field:  a.fieldName,
+		}
 	}
 
 	// SetBytes doesn't work with array bytes
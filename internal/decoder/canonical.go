@@ -0,0 +1,179 @@
+package decoder
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/trim21/go-bencode/internal/errors"
+)
+
+type DuplicateKeyPolicy uint8
+
+const (
+	DuplicateKeyLastWins DuplicateKeyPolicy = iota
+	DuplicateKeyFirstWins
+	DuplicateKeyError
+)
+
+// Canonicalize parses data with relaxed rules, and encodes it again in canonical form.
+func Canonicalize(data []byte, policy DuplicateKeyPolicy) ([]byte, error) {
+	// fast path, most input should be canonical already.
+	if Validate(data, false) == nil {
+		return slices.Clone(data), nil
+	}
+
+	c := canonicalizer{buf: data, policy: policy}
+
+	out, end, err := c.value(make([]byte, 0, len(data)), 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateEndBuf(data, end); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+type canonicalizer struct {
+	buf    []byte
+	policy DuplicateKeyPolicy
+}
+
+type canonicalEntry struct {
+	key    []byte
+	offset int // offset of key in input
+	start  int // canonical value in dictionary's buffer
+	end    int
+}
+
+func (c *canonicalizer) value(dst []byte, cursor int, depth int64) ([]byte, int, error) {
+	buf := c.buf
+	if cursor >= len(buf) {
+		return nil, 0, errors.DataTooShort(cursor)
+	}
+
+	switch buf[cursor] {
+	case 'i':
+		_, end, err := decodeIntegerBytes(buf, cursor)
+		if err != nil {
+			return nil, 0, err
+		}
+		return append(dst, buf[cursor:end]...), end, nil
+	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		_, end, err := readString(buf, cursor)
+		if err != nil {
+			return nil, 0, err
+		}
+		return append(dst, buf[cursor:end]...), end, nil
+	case 'l':
+		return c.list(dst, cursor, depth)
+	case 'd':
+		return c.dict(dst, cursor, depth)
+	}
+
+	return nil, 0, errors.ErrInvalidBeginningOfValue(buf[cursor], cursor)
+}
+
+func (c *canonicalizer) list(dst []byte, cursor int, depth int64) ([]byte, int, error) {
+	depth++
+	if depth > maxDecodeNestingDepth {
+		return nil, 0, errors.ErrExceededMaxDepth(maxDecodeNestingDepth, cursor)
+	}
+
+	dst = append(dst, 'l')
+	cursor++
+
+	for {
+		if cursor >= len(c.buf) {
+			return nil, 0, errors.DataTooShort(cursor)
+		}
+
+		if c.buf[cursor] == 'e' {
+			return append(dst, 'e'), cursor + 1, nil
+		}
+
+		var err error
+		dst, cursor, err = c.value(dst, cursor, depth)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+}
+
+func (c *canonicalizer) dict(dst []byte, cursor int, depth int64) ([]byte, int, error) {
+	depth++
+	if depth > maxDecodeNestingDepth {
+		return nil, 0, errors.ErrExceededMaxDepth(maxDecodeNestingDepth, cursor)
+	}
+
+	cursor++
+
+	// values are encoded into tmp first, then copied to dst in key order.
+	var tmp []byte
+	var entries []canonicalEntry
+
+	for {
+		if cursor >= len(c.buf) {
+			return nil, 0, errors.DataTooShort(cursor)
+		}
+
+		if c.buf[cursor] == 'e' {
+			cursor++
+			break
+		}
+
+		key, valueCursor, err := readString(c.buf, cursor)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if valueCursor >= len(c.buf) {
+			return nil, 0, errors.DataTooShort(valueCursor)
+		}
+
+		start := len(tmp)
+		tmp, valueCursor, err = c.value(tmp, valueCursor, depth)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		entries = append(entries, canonicalEntry{key: key, offset: cursor, start: start, end: len(tmp)})
+		cursor = valueCursor
+	}
+
+	// stable sort keeps duplicate keys in input order.
+	slices.SortStableFunc(entries, func(a, b canonicalEntry) int {
+		return bytes.Compare(a.key, b.key)
+	})
+
+	dst = append(dst, 'd')
+
+	for i := 0; i < len(entries); {
+		j := i + 1
+		for j < len(entries) && bytes.Equal(entries[i].key, entries[j].key) {
+			j++
+		}
+
+		e := entries[j-1]
+		switch {
+		case j-i == 1:
+		case c.policy == DuplicateKeyError:
+			return nil, 0, errors.ErrSyntax(fmt.Sprintf("bencode: dictionary contains duplicated keys %q", e.key), e.offset)
+		case c.policy == DuplicateKeyFirstWins:
+			e = entries[i]
+		}
+
+		dst = strconv.AppendInt(dst, int64(len(e.key)), 10)
+		dst = append(dst, ':')
+		dst = append(dst, e.key...)
+		dst = append(dst, tmp[e.start:e.end]...)
+
+		i = j
+	}
+
+	return append(dst, 'e'), cursor, nil
+}
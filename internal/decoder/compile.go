@@ -1,9 +1,10 @@
 package decoder
 
 import (
-	"fmt"
 	"reflect"
 	"sync/atomic"
+
+	"github.com/trim21/go-bencode/internal/errors"
 )
 
 var (
@@ -46,7 +47,7 @@ func compile(rt reflect.Type, structName, fieldName string, structTypeToDecoder
 	switch {
 	case reflect.PointerTo(rt).Implements(unmarshalerType):
 		return newUnmarshalerDecoder(reflect.PointerTo(rt), structName, fieldName), nil
-	case rt == bytesType:
+	case isBytesSlice(rt):
 		return newByteSliceDecoder(rt, structName, fieldName), nil
 	case rt.Kind() == reflect.Array && rt.Elem().Kind() == reflect.Uint8:
 		return newByteArrayDecoder(rt, structName, fieldName), nil
@@ -91,7 +92,7 @@ func compileMapKey(rt reflect.Type, structName, fieldName string, structTypeToDe
 	case rt.Kind() == reflect.Array && rt.Elem().Kind() == reflect.Uint8:
 		return newByteArrayDecoder(rt, structName, fieldName), nil
 	default:
-		return nil, fmt.Errorf("bencode only support [...]byte or string as map key")
+		return nil, errors.ErrUnsupportedType(rt, "bencode only support [...]byte or string as map key")
 	}
 }
 
@@ -131,7 +132,7 @@ func compileArray(rt reflect.Type, structName, fieldName string, structTypeToDec
 
 func compileInterface(rt reflect.Type, structName, fieldName string) (Decoder, error) {
 	if rt.NumMethod() != 0 {
-		return nil, fmt.Errorf("bencode: can only decode to empty interface, %s is not supported", rt.String())
+		return nil, errors.ErrUnsupportedType(rt, "can only decode to empty interface")
 	}
 
 	return newInterfaceDecoder(rt, structName, fieldName), nil
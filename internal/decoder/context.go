@@ -2,11 +2,35 @@ package decoder
 
 import (
 	"sync"
+	"unsafe"
+
+	"github.com/trim21/go-bencode/internal/errors"
+)
+
+// Limits bounds the resources used to decode untrusted input.
+// Zero value of a field means no limit, except MaxDepth which defaults to 10000.
+type Limits struct {
+	MaxDepth        int   // max nesting of lists and dictionaries
+	MaxStringLength int   // max length of a decoded string
+	MaxListLength   int   // max number of elements in a decoded list
+	MaxDictEntries  int   // max number of entries in a decoded dictionary
+	MaxAllocBytes   int64 // approximate memory budget for decoded strings, slices, maps and any values
+}
+
+// memory used by values decoded into any, for Limits.MaxAllocBytes.
+const (
+	anySize    = int64(unsafe.Sizeof(any(nil)))
+	stringSize = int64(unsafe.Sizeof(""))
 )
 
 type Context struct {
-	Buf     []byte
-	Relaxed bool
+	Buf []byte
+	Options
+
+	// Errors holds type errors recorded by containers with Options.CollectErrors.
+	Errors []error
+
+	allocated int64
 }
 
 var ctxPool = sync.Pool{
@@ -21,6 +45,62 @@ func newCtx() *Context {
 
 func freeCtx(ctx *Context) {
 	ctx.Buf = nil
-	ctx.Relaxed = false
+	ctx.Options = Options{}
+	ctx.Errors = nil
+	ctx.allocated = 0
 	ctxPool.Put(ctx)
 }
+
+func (ctx *Context) checkDepth(depth int64, cursor int) error {
+	maxDepth := int64(ctx.Limits.MaxDepth)
+	if maxDepth <= 0 {
+		maxDepth = maxDecodeNestingDepth
+	}
+
+	if depth > maxDepth {
+		return errors.ErrExceededMaxDepth(maxDepth, cursor)
+	}
+
+	return nil
+}
+
+// checkString is called before a string of size is copied into Go value.
+func (ctx *Context) checkString(size int, cursor int) error {
+	if ctx.Limits.MaxStringLength > 0 && size > ctx.Limits.MaxStringLength {
+		return errors.ErrLimit("MaxStringLength", int64(ctx.Limits.MaxStringLength), cursor)
+	}
+
+	return ctx.alloc(int64(size), cursor)
+}
+
+// checkListLength is called before n-th element of a list is decoded.
+func (ctx *Context) checkListLength(n int, cursor int) error {
+	if ctx.Limits.MaxListLength > 0 && n > ctx.Limits.MaxListLength {
+		return errors.ErrLimit("MaxListLength", int64(ctx.Limits.MaxListLength), cursor)
+	}
+
+	return nil
+}
+
+// checkDictEntries is called before n-th entry of a dictionary is decoded.
+func (ctx *Context) checkDictEntries(n int, cursor int) error {
+	if ctx.Limits.MaxDictEntries > 0 && n > ctx.Limits.MaxDictEntries {
+		return errors.ErrLimit("MaxDictEntries", int64(ctx.Limits.MaxDictEntries), cursor)
+	}
+
+	return nil
+}
+
+// alloc charges size bytes to allocation budget.
+func (ctx *Context) alloc(size int64, cursor int) error {
+	if ctx.Limits.MaxAllocBytes <= 0 {
+		return nil
+	}
+
+	ctx.allocated += size
+	if ctx.allocated > ctx.Limits.MaxAllocBytes {
+		return errors.ErrLimit("MaxAllocBytes", ctx.Limits.MaxAllocBytes, cursor)
+	}
+
+	return nil
+}
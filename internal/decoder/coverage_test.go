@@ -192,9 +192,9 @@ func TestSyntaxHelpersRejectMalformedValues(t *testing.T) {
 		name string
 		call func() error
 	}{
-		{name: "list ends early", call: func() error { _, err := skipList([]byte("l"), 0, 0, false); return err }},
-		{name: "dictionary ends early", call: func() error { _, err := skipDictionary([]byte("d"), 0, 0, false); return err }},
-		{name: "not a dictionary", call: func() error { _, err := skipDictionary([]byte("le"), 0, 0, false); return err }},
+		{name: "list ends early", call: func() error { _, err := skipList(&Context{Buf: []byte("l")}, 0, 0); return err }},
+		{name: "dictionary ends early", call: func() error { _, err := skipDictionary(&Context{Buf: []byte("d")}, 0, 0); return err }},
+		{name: "not a dictionary", call: func() error { _, err := skipDictionary(&Context{Buf: []byte("le")}, 0, 0); return err }},
 		{name: "missing string length", call: func() error { _, _, err := readString([]byte(":x"), 0); return err }},
 		{name: "missing colon", call: func() error { _, _, err := readString([]byte("1x"), 0); return err }},
 		{name: "invalid string length", call: func() error { _, _, err := readString([]byte("x:y"), 0); return err }},
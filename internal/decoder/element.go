@@ -0,0 +1,88 @@
+package decoder
+
+import (
+	"reflect"
+
+	"github.com/trim21/go-bencode/internal/errors"
+)
+
+// element is the dictionary key or list index of a value being decoded,
+// it's only converted to errors.PathElement when there is an error.
+type element struct {
+	key     []byte
+	index   int
+	isIndex bool
+	top     bool // top-level value, doesn't add to path
+}
+
+func keyElement(key []byte) element {
+	return element{key: key}
+}
+
+func indexElement(index int) element {
+	return element{index: index, isIndex: true}
+}
+
+func (e element) withPath(err error) error {
+	switch {
+	case e.top:
+		return err
+	case e.isIndex:
+		return errors.WithPath(err, errors.IndexElement(e.index))
+	default:
+		return errors.WithPath(err, errors.KeyElement(string(e.key)))
+	}
+}
+
+// decodeElement decodes a value of container into rv, and adds el to the path of errors.
+//
+// If ctx.CollectErrors is set, a type error doesn't stop decoding:
+// the value is skipped, rv is set to zero value, and the error is recorded in ctx.Errors.
+func decodeElement(ctx *Context, dec Decoder, cursor int, depth int64, rv reflect.Value, el element) (int, error) {
+	if !ctx.CollectErrors {
+		end, err := dec.Decode(ctx, cursor, depth, rv)
+		if err != nil {
+			return 0, el.withPath(err)
+		}
+
+		return end, nil
+	}
+
+	n := len(ctx.Errors)
+
+	end, err := dec.Decode(ctx, cursor, depth, rv)
+
+	for _, collected := range ctx.Errors[n:] {
+		el.withPath(collected)
+	}
+
+	if err == nil {
+		return end, nil
+	}
+
+	err = el.withPath(err)
+	if !isTypeError(err) {
+		return 0, err
+	}
+
+	end, skipErr := skipValue(ctx, cursor, depth)
+	if skipErr != nil {
+		return 0, el.withPath(skipErr)
+	}
+
+	rv.SetZero()
+	ctx.Errors = append(ctx.Errors, err)
+
+	return end, nil
+}
+
+// isTypeError reports whether err is about a well-formed value that can't be stored in Go value,
+// so decoding can continue with next value.
+func isTypeError(err error) bool {
+	switch err.(type) {
+	case *errors.UnmarshalTypeError, *errors.UnmarshalerError:
+		return true
+	}
+
+	return false
+}
@@ -2,7 +2,6 @@ package decoder
 
 import (
 	"bytes"
-	"fmt"
 	"math/big"
 	"reflect"
 	"strconv"
@@ -78,8 +77,77 @@ func decodeIntegerBytes(buf []byte, cursor int) ([]byte, int, error) {
 	return b, cursor + e + 1, nil
 }
 
+// decodeInteger is like decodeIntegerBytes, with Options.LenientNumbers.
+func (ctx *Context) decodeInteger(cursor int) ([]byte, int, error) {
+	if ctx.LenientNumbers {
+		return decodeLenientIntegerBytes(ctx.Buf, cursor)
+	}
+
+	return decodeIntegerBytes(ctx.Buf, cursor)
+}
+
+var zeroIntBytes = []byte("0")
+
+// decodeLenientIntegerBytes also accepts integers written by broken encoders,
+// like `i03e`, `i-0e`, `i+5e` and `i 5 e`, and returns them in canonical form.
+func decodeLenientIntegerBytes(buf []byte, cursor int) ([]byte, int, error) {
+	if buf[cursor] != 'i' {
+		return nil, cursor, errors.ErrExpecting("integer", buf, cursor)
+	}
+	cursor++
+
+	e := bytes.IndexByte(buf[cursor:], 'e')
+	if e == -1 {
+		return nil, cursor, errors.ErrSyntax("invalid integer, missing ending char 'e'", cursor)
+	}
+
+	lo, hi := cursor, cursor+e
+	for lo < hi && isLenientSpace(buf[lo]) {
+		lo++
+	}
+	for hi > lo && isLenientSpace(buf[hi-1]) {
+		hi--
+	}
+
+	sign := -1
+	if lo < hi && (buf[lo] == '-' || buf[lo] == '+') {
+		sign = lo
+		lo++
+	}
+
+	if lo == hi || !validIntBytes(buf[lo:hi]) {
+		return nil, cursor, errors.ErrSyntax("invalid int", cursor)
+	}
+
+	end := cursor + e + 1
+
+	for lo < hi-1 && buf[lo] == '0' {
+		lo++
+	}
+
+	switch {
+	case buf[lo] == '0':
+		return zeroIntBytes, end, nil
+	case sign == -1 || buf[sign] == '+':
+		return buf[lo:hi], end, nil
+	case sign == lo-1:
+		return buf[sign:hi], end, nil
+	}
+
+	// negative with leading zeros, the only case that needs a copy.
+	return append([]byte{'-'}, buf[lo:hi]...), end, nil
+}
+
+func isLenientSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
 func (d *intDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect.Value) (int, error) {
-	buf, c, err := decodeIntegerBytes(ctx.Buf, cursor)
+	if ctx.Buf[cursor] != 'i' {
+		return 0, errUnexpectedKind(ctx.Buf, cursor, rv.Type())
+	}
+
+	buf, c, err := ctx.decodeInteger(cursor)
 	if err != nil {
 		return 0, err
 	}
@@ -91,12 +159,8 @@ func (d *intDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect.Va
 
 func (d *intDecoder) processBytes(bytes []byte, cursor int, rv reflect.Value) (int, error) {
 	i64, err := strconv.ParseInt(string(bytes), 10, 64)
-	if err != nil {
-		return 0, fmt.Errorf("failed to decode int from bencode: %w", err)
-	}
-
-	if rv.OverflowInt(i64) {
-		return 0, errors.ErrValueOverflow(i64, rv.Type().Kind().String())
+	if err != nil || rv.OverflowInt(i64) {
+		return 0, errors.ErrValueOverflow(bytes, d.rt, cursor)
 	}
 
 	rv.SetInt(i64)
@@ -128,7 +192,11 @@ type bigIntPtrDecoder struct {
 }
 
 func (b *bigIntPtrDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect.Value) (int, error) {
-	buf, c, err := decodeIntegerBytes(ctx.Buf, cursor)
+	if ctx.Buf[cursor] != 'i' {
+		return 0, errUnexpectedKind(ctx.Buf, cursor, typeBigInt)
+	}
+
+	buf, c, err := ctx.decodeInteger(cursor)
 	if err != nil {
 		return 0, err
 	}
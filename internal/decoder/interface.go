@@ -1,8 +1,6 @@
 package decoder
 
 import (
-	"bytes"
-	"fmt"
 	"reflect"
 	"strconv"
 
@@ -55,18 +53,24 @@ func (d *interfaceDecoder) decodeAny(ctx *Context, cursor int, depth int64) (any
 	case 'l':
 		return d.decodeList(ctx, cursor, depth)
 	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-		b, end, err := readString(buf, cursor)
+		b, end, err := ctx.readString(cursor)
 		if err != nil {
 			return nil, 0, err
 		}
+		if err := ctx.checkString(len(b), cursor); err != nil {
+			return nil, 0, err
+		}
 		return string(b), end, nil
 	case 'i':
-		v, end, err := decodeIntegerBytes(buf, cursor)
+		v, end, err := ctx.decodeInteger(cursor)
 		if err != nil {
 			return nil, 0, err
 		}
 		i, err := strconv.ParseInt(string(v), 10, 64)
-		return i, end, err
+		if err != nil {
+			return nil, 0, errors.ErrValueOverflow(v, reflect.TypeFor[int64](), cursor)
+		}
+		return i, end, nil
 	}
 
 	return nil, cursor, errors.ErrInvalidBeginningOfValue(buf[cursor], cursor)
@@ -76,26 +80,26 @@ func (d *interfaceDecoder) decodeList(ctx *Context, cursor int, depth int64) ([]
 	buf := ctx.Buf
 
 	depth++
-	if depth > maxDecodeNestingDepth {
-		return nil, 0, errors.ErrExceededMaxDepth(buf[cursor], cursor)
+	if err := ctx.checkDepth(depth, cursor); err != nil {
+		return nil, 0, err
 	}
 
 	bufSize := len(buf)
 	if cursor >= bufSize {
-		return nil, 0, errors.DataTooShort()
+		return nil, 0, errors.DataTooShort(cursor)
 	}
 
 	cursor++
 
 	if bufSize < 2 {
-		return nil, 0, errors.DataTooShort()
+		return nil, 0, errors.DataTooShort(cursor)
 	}
 
 	var r = make([]any, 0, 8)
 
 	for {
 		if cursor >= bufSize {
-			return nil, 0, errors.DataTooShort()
+			return nil, 0, errors.DataTooShort(cursor)
 		}
 
 		if buf[cursor] == 'e' {
@@ -103,9 +107,17 @@ func (d *interfaceDecoder) decodeList(ctx *Context, cursor int, depth int64) ([]
 			return r, cursor, nil
 		}
 
+		if err := ctx.checkListLength(len(r)+1, cursor); err != nil {
+			return nil, 0, err
+		}
+
+		if err := ctx.alloc(anySize, cursor); err != nil {
+			return nil, 0, err
+		}
+
 		v, end, err := d.decodeAny(ctx, cursor, depth)
 		if err != nil {
-			return nil, 0, err
+			return nil, 0, errors.WithPath(err, errors.IndexElement(len(r)))
 		}
 
 		r = append(r, v)
@@ -118,28 +130,29 @@ func (d *interfaceDecoder) decodeDict(ctx *Context, cursor int, depth int64) (ma
 	buf := ctx.Buf
 
 	depth++
-	if depth > maxDecodeNestingDepth {
-		return nil, 0, errors.ErrExceededMaxDepth(buf[cursor], cursor)
+	if err := ctx.checkDepth(depth, cursor); err != nil {
+		return nil, 0, err
 	}
 
 	bufSize := len(buf)
 	if cursor >= bufSize {
-		return nil, 0, errors.DataTooShort()
+		return nil, 0, errors.DataTooShort(cursor)
 	}
 
 	cursor++
 
 	if bufSize < 2 {
-		return nil, 0, errors.DataTooShort()
+		return nil, 0, errors.DataTooShort(cursor)
 	}
 
 	var m = make(map[string]any, 8)
 
 	var lastKey []byte
+	var entries int
 
 	for {
 		if cursor >= bufSize {
-			return nil, 0, errors.DataTooShort()
+			return nil, 0, errors.DataTooShort(cursor)
 		}
 
 		if buf[cursor] == 'e' {
@@ -147,26 +160,37 @@ func (d *interfaceDecoder) decodeDict(ctx *Context, cursor int, depth int64) (ma
 			return m, cursor, nil
 		}
 
-		rawKey, keyCursor, err := readString(buf, cursor)
+		rawKey, keyCursor, err := ctx.readString(cursor)
 		if err != nil {
 			return nil, 0, err
 		}
 
 		if lastKey != nil && !ctx.Relaxed {
-			switch bytes.Compare(lastKey, rawKey) {
-			case 0:
-				return nil, cursor, fmt.Errorf("dictionary conrains duplicated keys %s. index %d", rawKey, cursor)
-			case 1:
-				return nil, cursor, fmt.Errorf("dictionary conrains unordered keys %s, %s. index %d", lastKey, rawKey, cursor)
+			if err := checkKeyOrder(lastKey, rawKey, cursor); err != nil {
+				return nil, 0, err
 			}
 		}
 
 		lastKey = rawKey
+
+		entries++
+		if err := ctx.checkDictEntries(entries, cursor); err != nil {
+			return nil, 0, err
+		}
+
+		if err := ctx.checkString(len(rawKey), cursor); err != nil {
+			return nil, 0, err
+		}
+
+		if err := ctx.alloc(stringSize+anySize, cursor); err != nil {
+			return nil, 0, err
+		}
+
 		cursor = keyCursor
 
 		v, end, err := d.decodeAny(ctx, cursor, depth)
 		if err != nil {
-			return nil, 0, err
+			return nil, 0, errors.WithPath(err, errors.KeyElement(string(rawKey)))
 		}
 
 		m[string(rawKey)] = v
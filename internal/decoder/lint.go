@@ -0,0 +1,261 @@
+package decoder
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	"github.com/trim21/go-bencode/internal/errors"
+)
+
+type IssueKind uint8
+
+const (
+	IssueSyntax IssueKind = iota + 1
+	IssueUnsortedKey
+	IssueDuplicateKey
+	IssueIntLeadingZero
+	IssueNegativeZero
+	IssueLengthLeadingZero
+	IssueTrailingData
+)
+
+func (k IssueKind) String() string {
+	switch k {
+	case IssueSyntax:
+		return "syntax error"
+	case IssueUnsortedKey:
+		return "unsorted dictionary key"
+	case IssueDuplicateKey:
+		return "duplicate dictionary key"
+	case IssueIntLeadingZero:
+		return "integer with leading zero"
+	case IssueNegativeZero:
+		return "negative zero"
+	case IssueLengthLeadingZero:
+		return "string length with leading zero"
+	case IssueTrailingData:
+		return "trailing data"
+	}
+
+	return fmt.Sprintf("IssueKind(%d)", k)
+}
+
+type Issue struct {
+	Kind   IssueKind
+	Offset int
+	Path   errors.Path
+	Err    error // only set for IssueSyntax
+}
+
+func (i Issue) String() string {
+	var s string
+	if i.Err != nil {
+		s = i.Err.Error()
+	} else {
+		s = i.Kind.String()
+	}
+
+	if len(i.Path) != 0 {
+		return fmt.Sprintf("%s at %s (offset %d)", s, i.Path, i.Offset)
+	}
+
+	return fmt.Sprintf("%s (offset %d)", s, i.Offset)
+}
+
+// Lint reports all non-canonical constructs in data.
+// Linting stops at the first syntax error, which is reported as the last issue.
+func Lint(data []byte) []Issue {
+	l := linter{buf: data}
+
+	if len(data) == 0 {
+		l.fail("bencode: empty data", 0)
+		return l.issues
+	}
+
+	end, ok := l.value(0, 0)
+	if ok && end != len(data) {
+		l.report(IssueTrailingData, end)
+	}
+
+	return l.issues
+}
+
+type linter struct {
+	buf    []byte
+	path   errors.Path
+	issues []Issue
+}
+
+func (l *linter) report(kind IssueKind, offset int) {
+	l.issues = append(l.issues, Issue{Kind: kind, Offset: offset, Path: slices.Clone(l.path)})
+}
+
+func (l *linter) fail(msg string, offset int) {
+	l.issues = append(l.issues, Issue{
+		Kind:   IssueSyntax,
+		Offset: offset,
+		Path:   slices.Clone(l.path),
+		Err:    errors.ErrSyntax(msg, offset),
+	})
+}
+
+func (l *linter) value(cursor int, depth int64) (int, bool) {
+	if cursor >= len(l.buf) {
+		l.fail("bencode: unexpected end of bencode input", cursor)
+		return 0, false
+	}
+
+	switch c := l.buf[cursor]; c {
+	case 'i':
+		return l.integer(cursor)
+	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		_, end, ok := l.string(cursor)
+		return end, ok
+	case 'l', 'd':
+		depth++
+		if depth > maxDecodeNestingDepth {
+			l.fail(fmt.Sprintf(`invalid character "%c" exceeded max depth`, c), cursor)
+			return 0, false
+		}
+
+		if c == 'l' {
+			return l.list(cursor, depth)
+		}
+		return l.dict(cursor, depth)
+	default:
+		l.fail(fmt.Sprintf("bencode: invalid character '%c' looking for beginning of value", c), cursor)
+		return 0, false
+	}
+}
+
+func (l *linter) integer(cursor int) (int, bool) {
+	e := bytes.IndexByte(l.buf[cursor+1:], 'e')
+	if e == -1 {
+		l.fail("invalid integer, missing ending char 'e'", cursor)
+		return 0, false
+	}
+
+	b := l.buf[cursor+1 : cursor+1+e]
+	digits := b
+	if len(digits) != 0 && digits[0] == '-' {
+		digits = digits[1:]
+	}
+
+	if len(digits) == 0 || !validIntBytes(digits) {
+		l.fail("invalid integer", cursor)
+		return 0, false
+	}
+
+	switch {
+	case b[0] == '-' && len(bytes.TrimLeft(digits, "0")) == 0:
+		l.report(IssueNegativeZero, cursor)
+	case len(digits) > 1 && digits[0] == '0':
+		l.report(IssueIntLeadingZero, cursor)
+	}
+
+	return cursor + e + 2, true
+}
+
+func (l *linter) string(cursor int) ([]byte, int, bool) {
+	colon := bytes.IndexByte(l.buf[cursor:], ':')
+	if colon <= 0 || !validIntBytes(l.buf[cursor:cursor+colon]) {
+		l.fail("invalid string length", cursor)
+		return nil, 0, false
+	}
+
+	sizeBuf := l.buf[cursor : cursor+colon]
+
+	size, err := parseUint64(sizeBuf)
+	if err != nil || size > uint64(len(l.buf)-(cursor+colon+1)) {
+		l.fail("invalid bytes, size overflow buffer", cursor)
+		return nil, 0, false
+	}
+
+	if len(sizeBuf) > 1 && sizeBuf[0] == '0' {
+		l.report(IssueLengthLeadingZero, cursor)
+	}
+
+	start := cursor + colon + 1
+	end := start + int(size)
+
+	return l.buf[start:end], end, true
+}
+
+func (l *linter) list(cursor int, depth int64) (int, bool) {
+	cursor++
+
+	for index := 0; ; index++ {
+		if cursor >= len(l.buf) {
+			l.fail("bencode: unexpected end of bencode input", cursor)
+			return 0, false
+		}
+
+		if l.buf[cursor] == 'e' {
+			return cursor + 1, true
+		}
+
+		l.path = append(l.path, errors.IndexElement(index))
+		end, ok := l.value(cursor, depth)
+		l.path = l.path[:len(l.path)-1]
+		if !ok {
+			return 0, false
+		}
+
+		cursor = end
+	}
+}
+
+func (l *linter) dict(cursor int, depth int64) (int, bool) {
+	cursor++
+
+	var lastKey []byte
+	var seen map[string]struct{}
+
+	for {
+		if cursor >= len(l.buf) {
+			l.fail("bencode: unexpected end of bencode input", cursor)
+			return 0, false
+		}
+
+		if l.buf[cursor] == 'e' {
+			return cursor + 1, true
+		}
+
+		if l.buf[cursor] < '0' || l.buf[cursor] > '9' {
+			l.fail("bencode: dictionary key must be a string", cursor)
+			return 0, false
+		}
+
+		keyStart := cursor
+		key, end, ok := l.string(cursor)
+		if !ok {
+			return 0, false
+		}
+
+		l.path = append(l.path, errors.KeyElement(string(key)))
+
+		if lastKey != nil {
+			if seen == nil {
+				seen = map[string]struct{}{string(lastKey): {}}
+			}
+
+			if _, dup := seen[string(key)]; dup {
+				l.report(IssueDuplicateKey, keyStart)
+			} else if bytes.Compare(lastKey, key) > 0 {
+				l.report(IssueUnsortedKey, keyStart)
+			}
+
+			seen[string(key)] = struct{}{}
+		}
+		lastKey = key
+
+		end, ok = l.value(end, depth)
+		l.path = l.path[:len(l.path)-1]
+		if !ok {
+			return 0, false
+		}
+
+		cursor = end
+	}
+}
@@ -1,8 +1,6 @@
 package decoder
 
 import (
-	"bytes"
-	"fmt"
 	"reflect"
 
 	"github.com/trim21/go-bencode/internal/errors"
@@ -49,22 +47,22 @@ func (d *mapDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect.Va
 
 	bufSize := len(buf)
 	if cursor >= bufSize {
-		return 0, errors.DataTooShort()
+		return 0, errors.DataTooShort(cursor)
 	}
 
 	if buf[cursor] != 'd' {
-		return 0, errors.ErrExpecting("dictionary", buf, cursor)
+		return 0, errUnexpectedKind(buf, cursor, rv.Type())
 	}
 
 	cursor++
 
 	depth++
-	if depth > maxDecodeNestingDepth {
-		return 0, errors.ErrExceededMaxDepth(buf[cursor-1], cursor-1)
+	if err := ctx.checkDepth(depth, cursor-1); err != nil {
+		return 0, err
 	}
 
 	if bufSize < 2 {
-		return 0, errors.DataTooShort()
+		return 0, errors.DataTooShort(cursor)
 	}
 
 	if rv.IsNil() {
@@ -72,10 +70,11 @@ func (d *mapDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect.Va
 	}
 
 	var lastKey []byte
+	var entries int
 
 	for {
 		if cursor >= bufSize {
-			return 0, errors.DataTooShort()
+			return 0, errors.DataTooShort(cursor)
 		}
 
 		if buf[cursor] == 'e' {
@@ -83,39 +82,54 @@ func (d *mapDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect.Va
 			return cursor, nil
 		}
 
-		currentKey, _, err := readString(buf, cursor)
-		if err != nil {
-			return 0, err
-		}
-
-		k := reflect.New(d.keyType).Elem()
-		keyCursor, err := d.keyDecoder.Decode(ctx, cursor, depth, k)
+		currentKey, keyEnd, err := ctx.readString(cursor)
 		if err != nil {
 			return 0, err
 		}
 
 		if lastKey != nil && !ctx.Relaxed {
-			switch bytes.Compare(lastKey, currentKey) {
-			case 0:
-				return cursor, fmt.Errorf("dictionary conrains duplicated keys %s. index %d", currentKey, cursor)
-			case 1:
-				return cursor, fmt.Errorf("dictionary conrains unordered keys %s, %s. index %d", lastKey, currentKey, cursor)
+			if err := checkKeyOrder(lastKey, currentKey, cursor); err != nil {
+				return 0, err
 			}
 		}
 		lastKey = currentKey
 
-		cursor = keyCursor
-		if cursor >= bufSize {
-			return 0, errors.DataTooShort()
+		if keyEnd >= bufSize {
+			return 0, errors.DataTooShort(keyEnd)
+		}
+
+		entries++
+		if err := ctx.checkDictEntries(entries, cursor); err != nil {
+			return 0, err
+		}
+
+		if err := ctx.alloc(int64(d.keyType.Size()+d.valueType.Size()), cursor); err != nil {
+			return 0, err
+		}
+
+		k := reflect.New(d.keyType).Elem()
+		_, err = d.keyDecoder.Decode(ctx, cursor, depth, k)
+		if err != nil {
+			err = errors.WithPath(err, errors.KeyElement(string(currentKey)))
+			if !ctx.CollectErrors || !isTypeError(err) {
+				return 0, err
+			}
+
+			// key can't be decoded, drop the whole entry.
+			ctx.Errors = append(ctx.Errors, err)
+			cursor, err = skipValue(ctx, keyEnd, depth)
+			if err != nil {
+				return 0, errors.WithPath(err, errors.KeyElement(string(currentKey)))
+			}
+			continue
 		}
 
 		v := reflect.New(d.valueType).Elem()
-		valueCursor, err := d.valueDecoder.Decode(ctx, cursor, depth, v)
+		cursor, err = decodeElement(ctx, d.valueDecoder, keyEnd, depth, v, keyElement(currentKey))
 		if err != nil {
 			return 0, err
 		}
 
 		rv.SetMapIndex(k, v)
-		cursor = valueCursor
 	}
 }
@@ -0,0 +1,75 @@
+package decoder
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/trim21/go-bencode/internal/errors"
+)
+
+// compilePacked compiles decoder for struct field with `packed` option,
+// `[][N]byte` is decoded from a single bencode string, split into elements of N bytes.
+func compilePacked(rt reflect.Type, structName, fieldName string) (Decoder, error) {
+	if rt.Kind() == reflect.Pointer {
+		dec, err := compilePacked(rt.Elem(), structName, fieldName)
+		if err != nil {
+			return nil, err
+		}
+		return newPtrDecoder(dec, rt.Elem(), structName, fieldName)
+	}
+
+	if rt.Kind() != reflect.Slice || rt.Elem().Kind() != reflect.Array ||
+		rt.Elem().Elem().Kind() != reflect.Uint8 || rt.Elem().Len() == 0 {
+		return nil, errors.ErrUnsupportedType(rt, "packed option requires a slice of non-empty byte arrays")
+	}
+
+	return &packedDecoder{
+		rt:         rt,
+		size:       rt.Elem().Len(),
+		structName: structName,
+		fieldName:  fieldName,
+	}, nil
+}
+
+type packedDecoder struct {
+	rt         reflect.Type
+	size       int
+	structName string
+	fieldName  string
+}
+
+func (d *packedDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect.Value) (int, error) {
+	if !isStringStart(ctx.Buf[cursor]) {
+		return 0, errUnexpectedKind(ctx.Buf, cursor, d.rt)
+	}
+
+	bytes, end, err := ctx.readString(cursor)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(bytes)%d.size != 0 {
+		return 0, &errors.UnmarshalTypeError{
+			Value:  fmt.Sprintf("string of length %d", len(bytes)),
+			Type:   d.rt,
+			Offset: cursor,
+			Struct: d.structName,
+			Field:  d.fieldName,
+		}
+	}
+
+	if err := ctx.checkString(len(bytes), cursor); err != nil {
+		return 0, err
+	}
+
+	length := len(bytes) / d.size
+	s := reflect.MakeSlice(d.rt, length, length)
+
+	// slice elements are always addressable
+	for i := range length {
+		copy(s.Index(i).Bytes(), bytes[i*d.size:])
+	}
+
+	rv.Set(s)
+	return end, nil
+}
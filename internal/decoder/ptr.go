@@ -29,6 +29,10 @@ func newPtrDecoder(dec Decoder, rt reflect.Type, structName, fieldName string) (
 
 func (d *ptrDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect.Value) (int, error) {
 	if rv.IsNil() {
+		if err := ctx.alloc(int64(d.rt.Size()), cursor); err != nil {
+			return 0, err
+		}
+
 		np := reflect.New(d.rt)
 		rv.Set(np)
 	}
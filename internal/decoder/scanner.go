@@ -0,0 +1,191 @@
+package decoder
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/trim21/go-bencode/internal/errors"
+)
+
+// Scanner finds where the first top-level value ends in a buffer that is still growing.
+//
+// It only checks the structure of the input, the value is fully validated when it's decoded.
+// Scanner keeps its position between calls, so a value arriving in many small reads
+// doesn't get re-scanned from the beginning each time.
+type Scanner struct {
+	cursor int // start of the first token that has not been scanned yet
+	depth  int64
+
+	// Limits.MaxDepth and Limits.MaxStringLength are checked while scanning,
+	// so a value exceeding them is rejected before it's fully read.
+	Limits Limits
+
+	LenientNumbers bool // see Options.LenientNumbers
+}
+
+func (s *Scanner) Reset() {
+	s.cursor = 0
+	s.depth = 0
+}
+
+// Scan continues scanning buf, which must begin with the bytes passed to previous calls.
+//
+// It returns the length of the first complete value and true,
+// or false if buf ends before the value does.
+func (s *Scanner) Scan(buf []byte) (int, bool, error) {
+	for s.cursor < len(buf) {
+		cursor := s.cursor
+
+		switch c := buf[cursor]; c {
+		case 'l', 'd':
+			s.depth++
+			if err := s.checkDepth(cursor); err != nil {
+				return 0, false, err
+			}
+			cursor++
+		case 'e':
+			if s.depth == 0 {
+				return 0, false, errors.ErrInvalidBeginningOfValue(c, cursor)
+			}
+			s.depth--
+			cursor++
+		case 'i':
+			end, ok, err := scanInteger(buf, cursor, s.LenientNumbers)
+			if err != nil || !ok {
+				return 0, false, err
+			}
+			cursor = end
+		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			end, ok, err := s.scanString(buf, cursor)
+			if err != nil || !ok {
+				return 0, false, err
+			}
+			cursor = end
+		default:
+			return 0, false, errors.ErrInvalidBeginningOfValue(c, cursor)
+		}
+
+		s.cursor = cursor
+
+		if s.depth == 0 {
+			return cursor, true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+func (s *Scanner) checkDepth(cursor int) error {
+	maxDepth := int64(s.Limits.MaxDepth)
+	if maxDepth <= 0 {
+		maxDepth = maxDecodeNestingDepth
+	}
+
+	if s.depth > maxDepth {
+		return errors.ErrExceededMaxDepth(maxDepth, cursor)
+	}
+
+	return nil
+}
+
+func scanInteger(buf []byte, cursor int, lenient bool) (int, bool, error) {
+	e := bytes.IndexByte(buf[cursor+1:], 'e')
+	if e != -1 {
+		return cursor + e + 2, true, nil
+	}
+
+	if lenient {
+		if i := invalidLenientIntByte(buf[cursor+1:]); i != -1 {
+			return 0, false, errors.ErrSyntax("invalid integer", cursor+1+i)
+		}
+
+		return 0, false, nil
+	}
+
+	// integer is not terminated yet, make sure what we have can still become one.
+	b := buf[cursor+1:]
+	if len(b) != 0 && b[0] == '-' {
+		b = b[1:]
+	}
+
+	if !validIntBytes(b) {
+		return 0, false, errors.ErrSyntax("invalid integer", cursor+1)
+	}
+
+	return 0, false, nil
+}
+
+// invalidLenientIntByte returns the index of the first byte of b
+// that can't be part of a lenient integer, or -1 if b may still become one.
+func invalidLenientIntByte(b []byte) int {
+	i := 0
+	for i < len(b) && isLenientSpace(b[i]) {
+		i++
+	}
+
+	if i < len(b) && (b[i] == '-' || b[i] == '+') {
+		i++
+	}
+
+	for i < len(b) && b[i] >= '0' && b[i] <= '9' {
+		i++
+	}
+
+	for i < len(b) && isLenientSpace(b[i]) {
+		i++
+	}
+
+	if i < len(b) {
+		return i
+	}
+
+	return -1
+}
+
+func (s *Scanner) scanString(buf []byte, cursor int) (int, bool, error) {
+	colon := bytes.IndexByte(buf[cursor:], ':')
+	if colon == -1 {
+		if validIntBytes(buf[cursor:]) {
+			// length only grows with more digits.
+			return 0, false, s.checkStringLength(buf[cursor:], cursor)
+		}
+
+		_, _, err := parseString(buf, cursor, s.LenientNumbers)
+		return 0, false, err
+	}
+
+	sizeBuf := buf[cursor : cursor+colon]
+	if validIntBytes(sizeBuf) {
+		if err := s.checkStringLength(sizeBuf, cursor); err != nil {
+			return 0, false, err
+		}
+
+		size, err := strconv.Atoi(string(sizeBuf))
+		if err == nil && size > len(buf)-(cursor+colon+1) {
+			return 0, false, nil
+		}
+	}
+
+	_, end, err := parseString(buf, cursor, s.LenientNumbers)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return end, true, nil
+}
+
+// checkStringLength checks the length prefix of a string against Limits.MaxStringLength,
+// a length too large to parse always exceeds it.
+func (s *Scanner) checkStringLength(sizeBuf []byte, cursor int) error {
+	maxLength := s.Limits.MaxStringLength
+	if maxLength <= 0 {
+		return nil
+	}
+
+	size, err := strconv.Atoi(string(sizeBuf))
+	if err != nil || size > maxLength {
+		return errors.ErrLimit("MaxStringLength", int64(maxLength), cursor)
+	}
+
+	return nil
+}
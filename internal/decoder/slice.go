@@ -1,7 +1,6 @@
 package decoder
 
 import (
-	"fmt"
 	"reflect"
 
 	"github.com/trim21/go-bencode/internal/errors"
@@ -31,16 +30,16 @@ func (d *sliceDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect.
 	buf := ctx.Buf
 	bufSize := len(buf)
 	if cursor >= bufSize {
-		return 0, errors.DataTooShort()
+		return 0, errors.DataTooShort(cursor)
 	}
 
 	depth++
-	if depth > maxDecodeNestingDepth {
-		return 0, errors.ErrExceededMaxDepth(buf[cursor], cursor)
+	if err := ctx.checkDepth(depth, cursor); err != nil {
+		return 0, err
 	}
 
 	if buf[cursor] != 'l' {
-		return 0, errors.ErrExpecting("list", buf, cursor)
+		return 0, errUnexpectedKind(buf, cursor, rv.Type())
 	}
 
 	cursor++
@@ -54,7 +53,7 @@ func (d *sliceDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect.
 
 	for {
 		if cursor >= bufSize {
-			return 0, fmt.Errorf("buffer overflow when decoding dictionary: %d", cursor)
+			return 0, errors.DataTooShort(cursor)
 		}
 
 		if buf[cursor] == 'e' {
@@ -63,13 +62,21 @@ func (d *sliceDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect.
 			return cursor + 1, nil
 		}
 
+		if err := ctx.checkListLength(index+1, cursor); err != nil {
+			return 0, err
+		}
+
+		if err := ctx.alloc(int64(d.elemType.Size()), cursor); err != nil {
+			return 0, err
+		}
+
 		if index == sCap {
 			s.Grow(sCap)
 			sCap = sCap * 2
 			s.SetLen(sCap)
 		}
 
-		c, err := d.valueDecoder.Decode(ctx, cursor, depth, s.Index(index))
+		c, err := decodeElement(ctx, d.valueDecoder, cursor, depth, s.Index(index), indexElement(index))
 		if err != nil {
 			return 0, err
 		}
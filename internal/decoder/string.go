@@ -17,10 +17,17 @@ func newStringDecoder(structName, fieldName string) *stringDecoder {
 }
 
 func (d *stringDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect.Value) (int, error) {
-	bytes, c, err := readString(ctx.Buf, cursor)
+	if !isStringStart(ctx.Buf[cursor]) {
+		return 0, errUnexpectedKind(ctx.Buf, cursor, rv.Type())
+	}
+
+	bytes, c, err := ctx.readString(cursor)
 	if err != nil {
 		return 0, err
 	}
+	if err := ctx.checkString(len(bytes), cursor); err != nil {
+		return 0, err
+	}
 	if len(bytes) != 0 {
 		rv.SetString(string(bytes))
 	}
@@ -0,0 +1,107 @@
+package decoder
+
+import (
+	"math/big"
+	"reflect"
+	"strconv"
+
+	"github.com/trim21/go-bencode/internal/errors"
+)
+
+// compileStringOption compiles decoder for struct field with `string` option,
+// ints, uints, bools and big.Int are decoded from bencode strings of their decimal value, or from integers.
+// Other types ignore the option.
+func compileStringOption(rt reflect.Type, structName, fieldName string, structTypeToDecoder map[reflect.Type]Decoder) (Decoder, error) {
+	if rt.Kind() == reflect.Pointer && rt != typeBigIntPtr {
+		dec, err := compileStringOption(rt.Elem(), structName, fieldName, structTypeToDecoder)
+		if err != nil {
+			return nil, err
+		}
+		return newPtrDecoder(dec, rt.Elem(), structName, fieldName)
+	}
+
+	dec, err := compile(rt, structName, fieldName, structTypeToDecoder)
+	if err != nil {
+		return nil, err
+	}
+
+	if reflect.PointerTo(rt).Implements(unmarshalerType) {
+		return dec, nil
+	}
+
+	switch rt.Kind() {
+	case reflect.Bool,
+		reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+	default:
+		if rt != typeBigInt && rt != typeBigIntPtr {
+			return dec, nil
+		}
+	}
+
+	return &stringOptionDecoder{rt: rt, dec: dec}, nil
+}
+
+type stringOptionDecoder struct {
+	rt  reflect.Type
+	dec Decoder // decoder of integer value
+}
+
+func (d *stringOptionDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect.Value) (int, error) {
+	if !isStringStart(ctx.Buf[cursor]) {
+		return d.dec.Decode(ctx, cursor, depth, rv)
+	}
+
+	s, end, err := ctx.readString(cursor)
+	if err != nil {
+		return 0, err
+	}
+
+	if !d.set(rv, string(s)) {
+		return 0, &errors.UnmarshalTypeError{
+			Value:  "string " + strconv.Quote(string(s)),
+			Type:   d.rt,
+			Offset: cursor,
+		}
+	}
+
+	return end, nil
+}
+
+// set parses s as the decimal value of rv, it reports false if s is invalid or overflows rv.
+func (d *stringOptionDecoder) set(rv reflect.Value, s string) bool {
+	switch d.rt {
+	case typeBigInt:
+		_, ok := rv.Addr().Interface().(*big.Int).SetString(s, 10)
+		return ok
+	case typeBigIntPtr:
+		v, ok := new(big.Int).SetString(s, 10)
+		if ok {
+			rv.Set(reflect.ValueOf(v))
+		}
+		return ok
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return false
+		}
+		rv.SetBool(b)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+		u, err := strconv.ParseUint(s, 10, 64)
+		if err != nil || rv.OverflowUint(u) {
+			return false
+		}
+		rv.SetUint(u)
+	default:
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || rv.OverflowInt(i) {
+			return false
+		}
+		rv.SetInt(i)
+	}
+
+	return true
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"fmt"
 	"reflect"
+	"slices"
+	"strings"
 
 	"github.com/trim21/go-bencode/internal/errors"
 	"github.com/trim21/go-bencode/internal/runtime"
@@ -13,8 +15,14 @@ func compileStruct(rt reflect.Type, structName, fieldName string, structTypeToDe
 	if dec, exists := structTypeToDecoder[rt]; exists {
 		return dec, nil
 	}
+
+	if runtime.StructOptionsFromType(rt).Tuple {
+		return compileTuple(rt, rt.Name(), structTypeToDecoder)
+	}
 	structDec := newStructDecoder(structName, fieldName, map[string]*structFieldDecoder{})
 	structDec.structName = rt.Name()
+	structDec.rt = rt
+	structDec.disallowUnknownFields = runtime.StructOptionsFromType(rt).DisallowUnknownFields
 	structTypeToDecoder[rt] = structDec
 	structName = rt.Name()
 
@@ -29,6 +37,19 @@ func compileStruct(rt reflect.Type, structName, fieldName string, structTypeToDe
 			continue
 		}
 
+		if tag.IsInline {
+			inline, err := compileInlineField(rt, field, structName, structTypeToDecoder)
+			if err != nil {
+				return nil, err
+			}
+
+			if structDec.inline != nil {
+				return nil, errors.ErrUnsupportedType(rt, "found multiple inline fields")
+			}
+			structDec.inline = inline
+			continue
+		}
+
 		var key string
 		if tag.Key != "" {
 			key = tag.Key
@@ -38,7 +59,7 @@ func compileStruct(rt reflect.Type, structName, fieldName string, structTypeToDe
 
 		if field.Anonymous {
 			if field.Type.Kind() != reflect.Struct {
-				return nil, fmt.Errorf("bencode: only support struct as Anonymous field, found: %s", field.Type.String())
+				return nil, errors.ErrUnsupportedType(field.Type, "only support struct as Anonymous field")
 			}
 
 			if field.Tag.Get("bencode") == "" {
@@ -47,12 +68,34 @@ func compileStruct(rt reflect.Type, structName, fieldName string, structTypeToDe
 					return nil, err
 				}
 
-				se := enc.(*structDecoder)
+				se, ok := enc.(*structDecoder)
+				if !ok {
+					return nil, errors.ErrUnsupportedType(field.Type, "tuple struct can't be embedded")
+				}
+
+				if se.inline != nil {
+					if structDec.inline != nil {
+						return nil, errors.ErrUnsupportedType(rt, "found multiple inline fields")
+					}
+
+					inline := *se.inline
+					inline.fieldIndex = append([]int{i}, inline.fieldIndex...)
+					structDec.inline = &inline
+				}
+
 				for _, dec := range se.fieldMap {
+					if dec.rank != 0 {
+						continue // alias, added again with its field
+					}
+
 					allFields = append(allFields, &structFieldDecoder{
-						dec:        dec.dec,
-						fieldIndex: append([]int{i}, dec.fieldIndex...),
-						key:        dec.key,
+						dec:          dec.dec,
+						fieldIndex:   append([]int{i}, dec.fieldIndex...),
+						key:          dec.key,
+						aliases:      dec.aliases,
+						isRequired:   dec.isRequired,
+						hasDefault:   dec.hasDefault,
+						defaultValue: dec.defaultValue,
 					})
 				}
 				continue
@@ -61,11 +104,11 @@ func compileStruct(rt reflect.Type, structName, fieldName string, structTypeToDe
 
 		if field.Type.Kind() == reflect.Pointer {
 			if field.Type.Elem().Kind() == reflect.Pointer {
-				return nil, fmt.Errorf("bencode: nested ptr field is not supported %s", rt.String())
+				return nil, errors.ErrUnsupportedType(field.Type, "nested ptr field is not supported")
 			}
 		}
 
-		dec, err := compile(field.Type, structName, key, structTypeToDecoder)
+		dec, err := compileFieldValue(tag, field.Type, structName, key, structTypeToDecoder)
 		if err != nil {
 			return nil, err
 		}
@@ -74,6 +117,16 @@ func compileStruct(rt reflect.Type, structName, fieldName string, structTypeToDe
 			dec:        dec,
 			fieldIndex: []int{i},
 			key:        key,
+			aliases:    tag.Aliases,
+			isRequired: tag.IsRequired,
+			hasDefault: tag.HasDefault,
+		}
+
+		if tag.HasDefault {
+			fieldSet.defaultValue, err = runtime.ParseDefault(field.Type, tag.Default)
+			if err != nil {
+				return nil, errors.ErrUnsupportedType(rt, fmt.Sprintf("field %s: %s", field.Name, err))
+			}
 		}
 
 		allFields = append(allFields, fieldSet)
@@ -82,11 +135,36 @@ func compileStruct(rt reflect.Type, structName, fieldName string, structTypeToDe
 	seen := map[string]bool{}
 	for _, dec := range allFields {
 		if seen[dec.key] {
-			return nil, fmt.Errorf("found duplicate keys in struct %s: %s", rt.String(), dec.key)
+			return nil, errors.ErrUnsupportedType(rt, fmt.Sprintf("found duplicate keys %s", dec.key))
 		}
 
 		seen[dec.key] = true
 		structDec.fieldMap[dec.key] = dec
+
+		if dec.isTracked() {
+			structDec.tracked = append(structDec.tracked, dec)
+		}
+	}
+
+	slices.SortFunc(structDec.tracked, func(a, b *structFieldDecoder) int {
+		return strings.Compare(a.key, b.key)
+	})
+	for i, dec := range structDec.tracked {
+		dec.trackedIndex = i
+	}
+
+	for _, dec := range allFields {
+		for i, alias := range dec.aliases {
+			if seen[alias] {
+				return nil, errors.ErrUnsupportedType(rt, fmt.Sprintf("found duplicate keys %s", alias))
+			}
+
+			seen[alias] = true
+
+			aliasDec := *dec
+			aliasDec.rank = i + 1
+			structDec.fieldMap[alias] = &aliasDec
+		}
 	}
 
 	delete(structTypeToDecoder, rt)
@@ -100,12 +178,31 @@ type structFieldDecoder struct {
 	dec Decoder
 
 	fieldIndex []int // for anonymous struct field
+
+	// keys of `alias=...` options. Primary key has rank 0, aliases have rank 1, 2, ... in tag order,
+	// a key with lower rank takes precedence if input has more than one of them.
+	aliases []string
+	rank    int
+
+	isRequired   bool
+	hasDefault   bool
+	defaultValue reflect.Value // value of `default=...` option
+	trackedIndex int           // index in structDecoder.tracked
+}
+
+func (f *structFieldDecoder) isTracked() bool {
+	return f.isRequired || f.hasDefault || len(f.aliases) != 0
 }
 
 type structDecoder struct {
 	fieldMap   map[string]*structFieldDecoder
 	structName string
 	fieldName  string
+
+	rt                    reflect.Type
+	disallowUnknownFields bool                  // set by a blank field with `bencode:",disallowunknown"` tag
+	tracked               []*structFieldDecoder // fields with required, default or alias options, sorted by key
+	inline                *inlineFieldDecoder   // field with `inline` option, nil if struct doesn't have one
 }
 
 func newStructDecoder(structName, fieldName string, fieldMap map[string]*structFieldDecoder) *structDecoder {
@@ -116,8 +213,8 @@ func newStructDecoder(structName, fieldName string, fieldMap map[string]*structF
 	}
 }
 
-func decodeKey(d *structDecoder, buf []byte, cursor int) ([]byte, int, *structFieldDecoder, error) {
-	key, c, err := readString(buf, cursor)
+func decodeKey(ctx *Context, d *structDecoder, cursor int) ([]byte, int, *structFieldDecoder, error) {
+	key, c, err := ctx.readString(cursor)
 	if err != nil {
 		return nil, 0, nil, err
 	}
@@ -136,53 +233,95 @@ func (d *structDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect
 	}
 
 	depth++
-	if depth > maxDecodeNestingDepth {
-		return 0, errors.ErrExceededMaxDepth(ctx.Buf[cursor], cursor)
+	if err := ctx.checkDepth(depth, cursor); err != nil {
+		return 0, err
 	}
 
 	if buf[cursor] != 'd' {
-		return 0, errors.ErrExpecting("dict", buf, cursor)
+		return 0, errUnexpectedKind(buf, cursor, rv.Type())
 	}
 
+	start := cursor
 	cursor++
 
 	var lastKey []byte
+	var entries int
+
+	// rank+1 of the key decoded into each tracked field, 0 if not found in input.
+	// the array avoids allocation for common structs.
+	var foundBuf [16]int
+	var found []int
+	if len(d.tracked) <= len(foundBuf) {
+		found = foundBuf[:len(d.tracked)]
+	} else {
+		found = make([]int, len(d.tracked))
+	}
 
 	for {
 		if cursor >= bufSize {
-			return 0, errors.DataTooShort()
+			return 0, errors.DataTooShort(cursor)
 		}
 
 		if buf[cursor] == 'e' {
 			cursor++
+
+			if err := d.setMissing(ctx, rv, found, start); err != nil {
+				return 0, err
+			}
+
 			return cursor, nil
 		}
 
-		currentKey, c, field, err := decodeKey(d, buf, cursor)
+		keyCursor := cursor
+		currentKey, c, field, err := decodeKey(ctx, d, cursor)
 		if err != nil {
 			return 0, err
 		}
 
 		if lastKey != nil && !ctx.Relaxed {
-			switch bytes.Compare(lastKey, currentKey) {
-			case 0:
-				return cursor, fmt.Errorf("dictionary conrains duplicated keys %s. index %d", currentKey, cursor)
-			case 1:
-				return cursor, fmt.Errorf("dictionary conrains unordered keys %s, %s. index %d", lastKey, currentKey, cursor)
+			if err := checkKeyOrder(lastKey, currentKey, cursor); err != nil {
+				return 0, err
 			}
 		}
 		lastKey = currentKey
 
+		entries++
+		if err := ctx.checkDictEntries(entries, cursor); err != nil {
+			return 0, err
+		}
+
 		cursor = c
 
 		if cursor >= bufSize {
-			return 0, errors.DataTooShort()
+			return 0, errors.DataTooShort(cursor)
 		}
 
 		if field == nil {
-			cursor, err = skipValue(buf, cursor, depth, ctx.Relaxed)
+			if d.inline != nil {
+				cursor, err = d.inline.decode(ctx, cursor, depth, rv, currentKey)
+				if err != nil {
+					return 0, err
+				}
+				continue
+			}
+
+			if d.disallowUnknownFields || ctx.DisallowUnknownFields {
+				err := errors.WithPath(&errors.UnknownFieldError{
+					Key:    string(currentKey),
+					Type:   d.rt,
+					Offset: keyCursor,
+				}, errors.KeyElement(string(currentKey)))
+
+				// the unknown entry is skipped like a value that doesn't fit its Go type.
+				if !ctx.CollectErrors {
+					return 0, err
+				}
+				ctx.Errors = append(ctx.Errors, err)
+			}
+
+			cursor, err = skipValue(ctx, cursor, depth)
 			if err != nil {
-				return 0, err
+				return 0, errors.WithPath(err, errors.KeyElement(string(currentKey)))
 			}
 			continue
 		}
@@ -192,9 +331,134 @@ func (d *structDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect
 			v = v.Field(index)
 		}
 
-		cursor, err = field.dec.Decode(ctx, cursor, depth, v)
+		if field.isTracked() {
+			if r := found[field.trackedIndex]; r != 0 && r <= field.rank {
+				// a key with higher precedence is already decoded.
+				cursor, err = skipValue(ctx, cursor, depth)
+				if err != nil {
+					return 0, errors.WithPath(err, errors.KeyElement(string(currentKey)))
+				}
+				continue
+			} else if r != 0 && r != field.rank+1 {
+				// replace value of an alias with lower precedence.
+				v.SetZero()
+			}
+
+			found[field.trackedIndex] = field.rank + 1
+		}
+
+		cursor, err = decodeElement(ctx, field.dec, cursor, depth, v, keyElement(currentKey))
 		if err != nil {
-			return 0, fmt.Errorf("bencode: failed to decode Go struct field %s.%s: %w", d.structName, field.key, err)
+			return 0, err
+		}
+	}
+}
+
+// setMissing sets default values of fields not found in input,
+// and returns an error listing keys of missing required fields.
+// With ctx.CollectErrors the error is recorded, and decoding continues.
+func (d *structDecoder) setMissing(ctx *Context, rv reflect.Value, found []int, cursor int) error {
+	var missing []string
+	for i, rank := range found {
+		if rank != 0 {
+			continue
+		}
+
+		field := d.tracked[i]
+		if field.isRequired {
+			missing = append(missing, field.key)
+			continue
+		}
+
+		if !field.hasDefault {
+			continue
+		}
+
+		v := rv
+		for _, index := range field.fieldIndex {
+			v = v.Field(index)
+		}
+
+		if v.Kind() == reflect.Slice {
+			// don't share default value with decoded structs.
+			v.SetBytes(bytes.Clone(field.defaultValue.Bytes()))
+		} else {
+			v.Set(field.defaultValue)
 		}
 	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	err := &errors.MissingFieldsError{Keys: missing, Type: d.rt, Offset: cursor}
+	if !ctx.CollectErrors {
+		return err
+	}
+
+	ctx.Errors = append(ctx.Errors, err)
+	return nil
+}
+
+// inlineFieldDecoder decodes keys without matching struct field into a map field with `inline` option.
+type inlineFieldDecoder struct {
+	fieldIndex []int
+	mapType    reflect.Type
+	valueType  reflect.Type
+	dec        Decoder
+}
+
+func compileInlineField(rt reflect.Type, field reflect.StructField, structName string, structTypeToDecoder map[reflect.Type]Decoder) (*inlineFieldDecoder, error) {
+	if field.Type.Kind() != reflect.Map || field.Type.Key().Kind() != reflect.String {
+		return nil, errors.ErrUnsupportedType(rt, fmt.Sprintf("inline field %s must be a map with string key", field.Name))
+	}
+
+	dec, err := compile(field.Type.Elem(), structName, field.Name, structTypeToDecoder)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inlineFieldDecoder{
+		fieldIndex: []int{field.Index[0]},
+		mapType:    field.Type,
+		valueType:  field.Type.Elem(),
+		dec:        dec,
+	}, nil
+}
+
+func (f *inlineFieldDecoder) decode(ctx *Context, cursor int, depth int64, rv reflect.Value, key []byte) (int, error) {
+	if err := ctx.alloc(int64(f.mapType.Key().Size()+f.valueType.Size()), cursor); err != nil {
+		return 0, err
+	}
+
+	m := rv
+	for _, index := range f.fieldIndex {
+		m = m.Field(index)
+	}
+
+	if m.IsNil() {
+		m.Set(reflect.MakeMap(f.mapType))
+	}
+
+	v := reflect.New(f.valueType).Elem()
+	end, err := decodeElement(ctx, f.dec, cursor, depth, v, keyElement(key))
+	if err != nil {
+		return 0, err
+	}
+
+	m.SetMapIndex(reflect.ValueOf(string(key)).Convert(f.mapType.Key()), v)
+
+	return end, nil
+}
+
+// compileFieldValue compiles decoder of field value for field options `string` and `packed`.
+func compileFieldValue(tag *runtime.StructTag, rt reflect.Type, structName, fieldName string, structTypeToDecoder map[reflect.Type]Decoder) (Decoder, error) {
+	switch {
+	case tag.IsPacked:
+		return compilePacked(rt, structName, fieldName)
+	case tag.IsString:
+		return compileStringOption(rt, structName, fieldName, structTypeToDecoder)
+	}
+
+	return compile(rt, structName, fieldName, structTypeToDecoder)
 }
@@ -3,59 +3,64 @@ package decoder
 import (
 	"bytes"
 	"fmt"
+	"reflect"
 	"strconv"
 
 	"github.com/trim21/go-bencode/internal/errors"
 )
 
-func skipString(buf []byte, cursor int) (int, error) {
-	_, end, err := readString(buf, cursor)
+func skipString(ctx *Context, cursor int) (int, error) {
+	_, end, err := ctx.readString(cursor)
 	return end, err
 }
 
-func skipInteger(buf []byte, cursor int) (int, error) {
-	_, end, err := decodeIntegerBytes(buf, cursor)
+func skipInteger(ctx *Context, cursor int) (int, error) {
+	_, end, err := ctx.decodeInteger(cursor)
 	return end, err
 }
 
-func skipList(buf []byte, cursor int, depth int64, relaxed bool) (int, error) {
+func skipList(ctx *Context, cursor int, depth int64) (int, error) {
+	buf := ctx.Buf
+
 	depth++
-	if depth > maxDecodeNestingDepth {
-		return 0, errors.ErrExceededMaxDepth(buf[cursor], cursor)
+	if err := ctx.checkDepth(depth, cursor); err != nil {
+		return 0, err
 	}
 
 	cursor++
 
 	bufSize := len(buf)
 
-	for {
+	for index := 0; ; index++ {
 		if cursor >= bufSize {
-			return 0, errors.DataTooShort()
+			return 0, errors.DataTooShort(cursor)
 		}
 
 		if buf[cursor] == 'e' {
 			return cursor + 1, nil
 		}
 
-		c, err := skipValue(buf, cursor, depth, relaxed)
+		c, err := skipValue(ctx, cursor, depth)
 		if err != nil {
-			return 0, err
+			return 0, errors.WithPath(err, errors.IndexElement(index))
 		}
 
 		cursor = c
 	}
 }
 
-func skipDictionary(buf []byte, cursor int, depth int64, relaxed bool) (int, error) {
+func skipDictionary(ctx *Context, cursor int, depth int64) (int, error) {
+	buf := ctx.Buf
+
 	depth++
-	if depth > maxDecodeNestingDepth {
-		return 0, errors.ErrExceededMaxDepth(buf[cursor], cursor)
+	if err := ctx.checkDepth(depth, cursor); err != nil {
+		return 0, err
 	}
 
 	bufSize := len(buf)
 
 	if cursor+2 > bufSize {
-		return 0, errors.DataTooShort()
+		return 0, errors.DataTooShort(cursor)
 	}
 
 	if buf[cursor] != 'd' {
@@ -67,7 +72,7 @@ func skipDictionary(buf []byte, cursor int, depth int64, relaxed bool) (int, err
 
 	for {
 		if cursor >= bufSize {
-			return 0, errors.DataTooShort()
+			return 0, errors.DataTooShort(cursor)
 		}
 
 		if buf[cursor] == 'e' {
@@ -75,17 +80,14 @@ func skipDictionary(buf []byte, cursor int, depth int64, relaxed bool) (int, err
 			return cursor, nil
 		}
 
-		currentKey, c, err := readString(buf, cursor)
+		currentKey, c, err := ctx.readString(cursor)
 		if err != nil {
 			return 0, err
 		}
 
-		if !relaxed && lastKey != nil {
-			switch bytes.Compare(lastKey, currentKey) {
-			case 0:
-				return cursor, fmt.Errorf("dictionary conrains duplicated keys %s. index %d", currentKey, cursor)
-			case 1:
-				return cursor, fmt.Errorf("dictionary conrains unordered keys %s, %s. index %d", lastKey, currentKey, cursor)
+		if !ctx.Relaxed && lastKey != nil {
+			if err := checkKeyOrder(lastKey, currentKey, cursor); err != nil {
+				return cursor, err
 			}
 		}
 		lastKey = currentKey
@@ -96,25 +98,91 @@ func skipDictionary(buf []byte, cursor int, depth int64, relaxed bool) (int, err
 			return 0, errors.ErrExpecting("object value after colon", buf, cursor)
 		}
 
-		c, err = skipValue(buf, cursor, depth, relaxed)
+		c, err = skipValue(ctx, cursor, depth)
 		if err != nil {
-			return 0, err
+			return 0, errors.WithPath(err, errors.KeyElement(string(currentKey)))
 		}
 		cursor = c
 	}
 }
 
+// Validate checks data is exactly one valid bencode value.
+func Validate(data []byte, relaxed bool) error {
+	if len(data) == 0 {
+		return errors.DataTooShort(0)
+	}
+
+	ctx := newCtx()
+	ctx.Buf = data
+	ctx.Relaxed = relaxed
+	end, err := skipValue(ctx, 0, 0)
+	freeCtx(ctx)
+	if err != nil {
+		return errors.FinishPath(err)
+	}
+
+	return validateEndBuf(data, end)
+}
+
+// checkKeyOrder make sure dictionary keys are sorted and unique.
+func checkKeyOrder(lastKey, currentKey []byte, cursor int) error {
+	switch bytes.Compare(lastKey, currentKey) {
+	case 0:
+		return errors.ErrSyntax(fmt.Sprintf("bencode: dictionary contains duplicated keys %s", currentKey), cursor)
+	case 1:
+		return errors.ErrSyntax(fmt.Sprintf("bencode: dictionary contains unordered keys %s, %s", lastKey, currentKey), cursor)
+	}
+
+	return nil
+}
+
+func isStringStart(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// valueKind describes the kind of value starting with c, for error messages.
+// It returns empty string if c can't start a value.
+func valueKind(c byte) string {
+	switch {
+	case c == 'i':
+		return "integer"
+	case c == 'l':
+		return "list"
+	case c == 'd':
+		return "dictionary"
+	case isStringStart(c):
+		return "string"
+	}
+
+	return ""
+}
+
+// errUnexpectedKind is returned by decoders when the value at cursor is not the kind they expect.
+func errUnexpectedKind(buf []byte, cursor int, rt reflect.Type) error {
+	if cursor >= len(buf) {
+		return errors.DataTooShort(cursor)
+	}
+
+	kind := valueKind(buf[cursor])
+	if kind == "" {
+		return errors.ErrInvalidBeginningOfValue(buf[cursor], cursor)
+	}
+
+	return &errors.UnmarshalTypeError{Value: kind, Type: rt, Offset: cursor}
+}
+
 // skip value with index also check syntax
-func skipValue(buf []byte, cursor int, depth int64, relaxed bool) (int, error) {
+func skipValue(ctx *Context, cursor int, depth int64) (int, error) {
+	buf := ctx.Buf
 	switch buf[cursor] {
 	case 'l':
-		return skipList(buf, cursor, depth, relaxed)
+		return skipList(ctx, cursor, depth)
 	case 'd':
-		return skipDictionary(buf, cursor, depth, relaxed)
+		return skipDictionary(ctx, cursor, depth)
 	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-		return skipString(buf, cursor)
+		return skipString(ctx, cursor)
 	case 'i':
-		return skipInteger(buf, cursor)
+		return skipInteger(ctx, cursor)
 	default:
 		return cursor, errors.ErrUnexpectedEnd("null", cursor)
 	}
@@ -124,31 +192,41 @@ func skipValue(buf []byte, cursor int, depth int64, relaxed bool) (int, error) {
 // parse `${length}:${content}` and return "${content}" as slice of buf.
 // return cursor set to start of next value
 func readString(buf []byte, cursor int) ([]byte, int, error) {
+	return parseString(buf, cursor, false)
+}
+
+// (*Context).readString is like readString, with Options.LenientNumbers.
+func (ctx *Context) readString(cursor int) ([]byte, int, error) {
+	return parseString(ctx.Buf, cursor, ctx.LenientNumbers)
+}
+
+// parseString is readString, lenient allows leading zeros in length.
+func parseString(buf []byte, cursor int, lenient bool) ([]byte, int, error) {
 	colon := bytes.IndexByte(buf[cursor:], ':')
 
 	if colon == -1 {
-		return nil, 0, fmt.Errorf("invalid bytes, failed find expected char ':'. index %d", cursor)
+		return nil, 0, errors.ErrSyntax("bencode: invalid bytes, failed find expected char ':'", cursor)
 	}
 
 	if colon == 0 {
-		return nil, 0, fmt.Errorf("invalid bytes, missing leading length. index %d", cursor)
+		return nil, 0, errors.ErrSyntax("bencode: invalid bytes, missing leading length", cursor)
 	}
 
 	sizeBuf := buf[cursor : cursor+colon]
 
 	if !validIntBytes(sizeBuf) {
-		return nil, 0, fmt.Errorf("invalid bytes, length is not valid int. index %d", cursor)
+		return nil, 0, errors.ErrSyntax("bencode: invalid bytes, length is not valid int", cursor)
 	}
 
-	if colon > 1 {
+	if colon > 1 && !lenient {
 		if sizeBuf[0] == '0' {
-			return nil, 0, fmt.Errorf("invalid bytes, leading 0 in length. index %d", cursor)
+			return nil, 0, errors.ErrSyntax("bencode: invalid bytes, leading 0 in length", cursor)
 		}
 	}
 
 	size, err := strconv.Atoi(string(sizeBuf))
 	if err != nil {
-		return nil, 0, fmt.Errorf("invalid bytes, length is not valid int. index %d", cursor)
+		return nil, 0, errors.ErrSyntax("bencode: invalid bytes, length is not valid int", cursor)
 	}
 
 	// size is attacker-controlled up to maxint64; subtract instead of adding so
@@ -0,0 +1,271 @@
+package decoder
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+
+	"github.com/trim21/go-bencode/internal/errors"
+)
+
+type TokenKind uint8
+
+const (
+	InvalidToken TokenKind = iota
+	DictStart
+	ListStart
+	End
+	Int
+	String
+)
+
+func (k TokenKind) String() string {
+	switch k {
+	case DictStart:
+		return "DictStart"
+	case ListStart:
+		return "ListStart"
+	case End:
+		return "End"
+	case Int:
+		return "Int"
+	case String:
+		return "String"
+	}
+
+	return "InvalidToken"
+}
+
+type Token struct {
+	Kind   TokenKind
+	Offset int
+
+	// Bytes is the digits of an Int token, or the content of a String token.
+	// It's a view into the input, not a copy.
+	Bytes []byte
+}
+
+// Int64 parses the digits of an Int token.
+func (t Token) Int64() (int64, error) {
+	if t.Kind != Int {
+		return 0, errors.ErrTypeMismatch(fmt.Sprintf("bencode: Int64 called on %s token", t.Kind), reflect.TypeFor[int64](), t.Offset)
+	}
+
+	i, err := strconv.ParseInt(string(t.Bytes), 10, 64)
+	if err != nil {
+		return 0, errors.ErrValueOverflow(t.Bytes, reflect.TypeFor[int64](), t.Offset)
+	}
+
+	return i, nil
+}
+
+type tokenFrame struct {
+	dict    bool
+	key     bool // next token of dict is a key
+	lastKey []byte
+}
+
+type TokenReader struct {
+	buf     []byte
+	cursor  int
+	relaxed bool
+
+	stack []tokenFrame
+	done  bool // top-level value is finished
+
+	peeked bool
+	peek   Token
+
+	err error
+}
+
+func NewTokenReader(data []byte, relaxed bool) TokenReader {
+	return TokenReader{buf: data, relaxed: relaxed}
+}
+
+func (r *TokenReader) Next() (Token, error) {
+	if r.peeked {
+		r.peeked = false
+		return r.peek, nil
+	}
+
+	if r.err != nil {
+		return Token{}, r.err
+	}
+
+	t, err := r.read()
+	if err != nil {
+		r.err = err
+		return Token{}, err
+	}
+
+	return t, nil
+}
+
+func (r *TokenReader) Peek() (Token, error) {
+	if r.peeked {
+		return r.peek, nil
+	}
+
+	t, err := r.Next()
+	if err != nil {
+		return Token{}, err
+	}
+
+	r.peeked = true
+	r.peek = t
+
+	return t, nil
+}
+
+func (r *TokenReader) Skip() error {
+	t, err := r.Next()
+	if err != nil {
+		return err
+	}
+
+	switch t.Kind {
+	case End:
+		return errors.ErrSyntax("bencode: no value to skip before end of container", t.Offset)
+	case DictStart, ListStart:
+		// Next already pushed the container, skipValue validates and consumes it as a whole.
+		ctx := newCtx()
+		ctx.Buf = r.buf
+		ctx.Relaxed = r.relaxed
+		end, err := skipValue(ctx, t.Offset, int64(len(r.stack)-1))
+		freeCtx(ctx)
+		if err != nil {
+			r.err = errors.FinishPath(err)
+			return r.err
+		}
+
+		r.cursor = end
+		r.stack = r.stack[:len(r.stack)-1]
+		r.finishValue()
+	}
+
+	return nil
+}
+
+func (r *TokenReader) read() (Token, error) {
+	buf := r.buf
+	cursor := r.cursor
+
+	if r.done {
+		if cursor == len(buf) {
+			return Token{}, io.EOF
+		}
+
+		return Token{}, errors.ErrSyntax(fmt.Sprintf("invalid character '%c' after top-level value", buf[cursor]), cursor)
+	}
+
+	if cursor >= len(buf) {
+		return Token{}, errors.DataTooShort(cursor)
+	}
+
+	var frame *tokenFrame
+	if len(r.stack) != 0 {
+		frame = &r.stack[len(r.stack)-1]
+	}
+
+	if frame != nil && frame.dict {
+		if frame.key {
+			return r.readKey(frame)
+		}
+
+		if buf[cursor] == 'e' {
+			return Token{}, errors.ErrExpecting("object value after key", buf, cursor)
+		}
+	}
+
+	switch c := buf[cursor]; c {
+	case 'e':
+		if frame == nil {
+			return Token{}, errors.ErrInvalidBeginningOfValue(c, cursor)
+		}
+
+		r.stack = r.stack[:len(r.stack)-1]
+		r.cursor = cursor + 1
+		r.finishValue()
+
+		return Token{Kind: End, Offset: cursor}, nil
+	case 'd', 'l':
+		if len(r.stack)+1 > maxDecodeNestingDepth {
+			return Token{}, errors.ErrExceededMaxDepth(maxDecodeNestingDepth, cursor)
+		}
+
+		r.stack = append(r.stack, tokenFrame{dict: c == 'd', key: c == 'd'})
+		r.cursor = cursor + 1
+
+		if c == 'd' {
+			return Token{Kind: DictStart, Offset: cursor}, nil
+		}
+		return Token{Kind: ListStart, Offset: cursor}, nil
+	case 'i':
+		b, end, err := decodeIntegerBytes(buf, cursor)
+		if err != nil {
+			return Token{}, err
+		}
+
+		r.cursor = end
+		r.finishValue()
+
+		return Token{Kind: Int, Offset: cursor, Bytes: b}, nil
+	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		b, end, err := readString(buf, cursor)
+		if err != nil {
+			return Token{}, err
+		}
+
+		r.cursor = end
+		r.finishValue()
+
+		return Token{Kind: String, Offset: cursor, Bytes: b}, nil
+	default:
+		return Token{}, errors.ErrInvalidBeginningOfValue(c, cursor)
+	}
+}
+
+func (r *TokenReader) readKey(frame *tokenFrame) (Token, error) {
+	buf := r.buf
+	cursor := r.cursor
+
+	if buf[cursor] == 'e' {
+		r.stack = r.stack[:len(r.stack)-1]
+		r.cursor = cursor + 1
+		r.finishValue()
+
+		return Token{Kind: End, Offset: cursor}, nil
+	}
+
+	key, end, err := readString(buf, cursor)
+	if err != nil {
+		return Token{}, err
+	}
+
+	if !r.relaxed && frame.lastKey != nil {
+		if err := checkKeyOrder(frame.lastKey, key, cursor); err != nil {
+			return Token{}, err
+		}
+	}
+
+	frame.lastKey = key
+	frame.key = false
+	r.cursor = end
+
+	return Token{Kind: String, Offset: cursor, Bytes: key}, nil
+}
+
+// finishValue is called after a complete value is read.
+func (r *TokenReader) finishValue() {
+	if len(r.stack) == 0 {
+		r.done = true
+		return
+	}
+
+	frame := &r.stack[len(r.stack)-1]
+	if frame.dict {
+		frame.key = true
+	}
+}
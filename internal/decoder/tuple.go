@@ -0,0 +1,112 @@
+package decoder
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/trim21/go-bencode/internal/errors"
+	"github.com/trim21/go-bencode/internal/runtime"
+)
+
+// tupleDecoder decodes a list into struct with `tuple` option, elements are fields in declaration order.
+type tupleDecoder struct {
+	rt     reflect.Type
+	fields []tupleField
+}
+
+type tupleField struct {
+	index int
+	dec   Decoder
+}
+
+func compileTuple(rt reflect.Type, structName string, structTypeToDecoder map[reflect.Type]Decoder) (Decoder, error) {
+	dec := &tupleDecoder{rt: rt}
+	structTypeToDecoder[rt] = dec
+
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		tag := runtime.StructTagFromField(field)
+		if tag.Key == "-" || runtime.IsIgnoredStructField(field) {
+			continue
+		}
+
+		fieldDec, err := compileFieldValue(tag, field.Type, structName, field.Name, structTypeToDecoder)
+		if err != nil {
+			return nil, err
+		}
+
+		dec.fields = append(dec.fields, tupleField{index: i, dec: fieldDec})
+	}
+
+	delete(structTypeToDecoder, rt)
+
+	return dec, nil
+}
+
+func (d *tupleDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect.Value) (int, error) {
+	buf := ctx.Buf
+	bufSize := len(buf)
+
+	depth++
+	if err := ctx.checkDepth(depth, cursor); err != nil {
+		return 0, err
+	}
+
+	if cursor >= bufSize {
+		return 0, errors.DataTooShort(cursor)
+	}
+
+	if buf[cursor] != 'l' {
+		return 0, errUnexpectedKind(buf, cursor, d.rt)
+	}
+
+	start := cursor
+	cursor++
+
+	for i, field := range d.fields {
+		if cursor >= bufSize {
+			return 0, errors.DataTooShort(cursor)
+		}
+
+		if buf[cursor] == 'e' {
+			return 0, d.lengthError(i, start)
+		}
+
+		c, err := decodeElement(ctx, field.dec, cursor, depth, rv.Field(field.index), indexElement(i))
+		if err != nil {
+			return 0, err
+		}
+
+		cursor = c
+	}
+
+	if cursor >= bufSize {
+		return 0, errors.DataTooShort(cursor)
+	}
+
+	if buf[cursor] != 'e' {
+		// count remaining elements for error message.
+		length := len(d.fields)
+		for cursor < bufSize && buf[cursor] != 'e' {
+			c, err := skipValue(ctx, cursor, depth)
+			if err != nil {
+				return 0, errors.WithPath(err, errors.IndexElement(length))
+			}
+
+			cursor = c
+			length++
+		}
+
+		if cursor >= bufSize {
+			return 0, errors.DataTooShort(cursor)
+		}
+
+		return 0, d.lengthError(length, start)
+	}
+
+	return cursor + 1, nil
+}
+
+func (d *tupleDecoder) lengthError(length int, cursor int) error {
+	return errors.ErrTypeMismatch(fmt.Sprintf("bencode: cannot unmarshal list of length %d into Go tuple struct %s with %d fields", length, d.rt, len(d.fields)), d.rt, cursor)
+}
@@ -32,13 +32,17 @@ func (d *uintDecoder) typeError(buf []byte, offset int) *errors.UnmarshalTypeErr
 }
 
 func (d *uintDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect.Value) (int, error) {
-	bytes, c, err := decodeIntegerBytes(ctx.Buf, cursor)
+	if ctx.Buf[cursor] != 'i' {
+		return 0, errUnexpectedKind(ctx.Buf, cursor, d.rt)
+	}
+
+	bytes, c, err := ctx.decodeInteger(cursor)
 	if err != nil {
 		return 0, err
 	}
 
 	if bytes[0] == '-' {
-		return 0, errors.ErrValueOverflow(string(bytes), rv.Type().Kind().String())
+		return 0, d.typeError(bytes, cursor)
 	}
 
 	cursor = c
@@ -53,7 +57,7 @@ func (d *uintDecoder) processBytes(bytes []byte, cursor int, rv reflect.Value) (
 	}
 
 	if rv.OverflowUint(u64) {
-		return 0, errors.ErrValueOverflow(u64, rv.Type().Kind().String())
+		return 0, d.typeError(bytes, cursor)
 	}
 
 	rv.SetUint(u64)
@@ -7,46 +7,96 @@ import (
 	"github.com/trim21/go-bencode/internal/errors"
 )
 
+// Options are the decoding settings carried by Context.
+type Options struct {
+	Relaxed bool // see UnmarshalRelaxed
+
+	// LenientNumbers accepts malformed integers like `i03e`, `i-0e`, `i+5e` or `i 5 e`,
+	// and string length with leading zeros like `03:abc`.
+	LenientNumbers bool
+
+	// CollectErrors makes containers record type errors of their elements in Context.Errors
+	// and continue with next element, all of them are returned as an *errors.UnmarshalErrors.
+	CollectErrors bool
+
+	// DisallowUnknownFields rejects dictionary keys without matching struct field
+	// with an *errors.UnknownFieldError, instead of skipping them.
+	DisallowUnknownFields bool
+
+	Limits Limits
+}
+
 func Unmarshal(data []byte, v any) error {
-	return unmarshal(data, v, false)
+	return unmarshal(data, v, Options{})
 }
 
 // UnmarshalRelaxed is like Unmarshal but with relaxed parsing rules:
 // - Dictionary keys are not required to be sorted
 // - Duplicate dictionary keys are allowed (last value wins)
 func UnmarshalRelaxed(data []byte, v any) error {
-	return unmarshal(data, v, true)
+	return unmarshal(data, v, Options{Relaxed: true})
 }
 
-func unmarshal(data []byte, v any, relaxed bool) error {
+func UnmarshalWithOptions(data []byte, v any, opts Options) error {
+	return unmarshal(data, v, opts)
+}
+
+// UnmarshalPrefix decodes the first value of data, and returns the length of decoded value.
+func UnmarshalPrefix(data []byte, v any, opts Options) (int, error) {
+	return decode(data, v, opts)
+}
+
+func unmarshal(data []byte, v any, opts Options) error {
+	cursor, err := decode(data, v, opts)
+	if err != nil {
+		if _, collected := err.(*errors.UnmarshalErrors); !collected {
+			return err
+		}
+	}
+
+	// syntax error after the value is reported instead of collected type errors.
+	if endErr := validateEndBuf(data, cursor); endErr != nil {
+		return endErr
+	}
+
+	return err
+}
+
+// decode returns end of the decoded value, even if type errors are collected.
+func decode(data []byte, v any, opts Options) (int, error) {
 	rv := reflect.ValueOf(v)
 	if !rv.IsValid() {
-		return &errors.InvalidUnmarshalError{}
+		return 0, &errors.InvalidUnmarshalError{}
 	}
 
 	rt := rv.Type()
 
 	if err := validateType(rt); err != nil {
-		return err
+		return 0, err
 	}
 	if rv.IsNil() {
-		return &errors.InvalidUnmarshalError{Type: rt}
+		return 0, &errors.InvalidUnmarshalError{Type: rt}
 	}
 
 	dec, err := CompileToGetDecoder(rt)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	ctx := newCtx()
 	ctx.Buf = data
-	ctx.Relaxed = relaxed
-	cursor, err := dec.Decode(ctx, 0, 0, rv.Elem())
+	ctx.Options = opts
+	cursor, err := decodeElement(ctx, dec, 0, 0, rv.Elem(), element{top: true})
+	collected := ctx.Errors
+	freeCtx(ctx)
 	if err != nil {
-		freeCtx(ctx)
-		return err
+		return 0, errors.FinishPath(err)
 	}
-	freeCtx(ctx)
-	return validateEndBuf(data, cursor)
+
+	if len(collected) != 0 {
+		return cursor, errors.FinishPath(&errors.UnmarshalErrors{Errors: collected})
+	}
+
+	return cursor, nil
 }
 
 func validateEndBuf(src []byte, cursor int) error {
@@ -56,7 +106,7 @@ func validateEndBuf(src []byte, cursor int) error {
 
 	return errors.ErrSyntax(
 		fmt.Sprintf("invalid character '%c' after top-level value", src[cursor]),
-		cursor+1,
+		cursor,
 	)
 }
 
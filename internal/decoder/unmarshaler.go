@@ -28,20 +28,24 @@ func newUnmarshalerDecoder(rt reflect.Type, structName, fieldName string) *unmar
 	}
 }
 
-func (d *unmarshalerDecoder) annotateError(cursor int, err error) {
+func (d *unmarshalerDecoder) annotateError(cursor int, err error) error {
 	switch e := err.(type) {
 	case *errors.UnmarshalTypeError:
 		e.Struct = d.structName
 		e.Field = d.fieldName
+		return e
 	case *errors.SyntaxError:
 		e.Offset = cursor
+		return e
 	}
+
+	return &errors.UnmarshalerError{Type: d.rt, Offset: cursor, Err: err}
 }
 
 func (d *unmarshalerDecoder) Decode(ctx *Context, cursor int, depth int64, rv reflect.Value) (int, error) {
 	buf := ctx.Buf
 	start := cursor
-	end, err := skipValue(buf, cursor, depth, ctx.Relaxed)
+	end, err := skipValue(ctx, cursor, depth)
 	if err != nil {
 		return 0, err
 	}
@@ -50,8 +54,7 @@ func (d *unmarshalerDecoder) Decode(ctx *Context, cursor int, depth int64, rv re
 	v := reflect.New(d.rt.Elem())
 
 	if err := v.Interface().(Unmarshaler).UnmarshalBencode(src); err != nil {
-		d.annotateError(cursor, err)
-		return 0, err
+		return 0, d.annotateError(cursor, err)
 	}
 
 	rv.Set(v.Elem())
@@ -2,6 +2,8 @@ package encoder
 
 import (
 	"reflect"
+
+	"github.com/trim21/go-bencode/internal/errors"
 )
 
 func compileArray(rt reflect.Type) (encoder, error) {
@@ -18,6 +20,11 @@ func compileArray(rt reflect.Type) (encoder, error) {
 		var err error // shadow compiler's error
 		for i := 0; i < size; i++ {
 			b, err = enc(ctx, b, rv.Index(i))
+			if err != nil {
+				return b, errors.WithPath(err, errors.IndexElement(i))
+			}
+
+			b, err = ctx.flush(b)
 			if err != nil {
 				return b, err
 			}
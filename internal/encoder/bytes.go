@@ -5,7 +5,12 @@ import (
 	"strconv"
 )
 
-var bytesType = reflect.TypeFor[[]byte]()
+// isBytesSlice reports whether rt is encoded as bencode string,
+// it's true for any slice of uint8 kind, including named types like `type InfoHash []byte`,
+// unless the element type implements Marshaler.
+func isBytesSlice(rt reflect.Type) bool {
+	return rt.Kind() == reflect.Slice && rt.Elem().Kind() == reflect.Uint8 && !rt.Elem().Implements(marshalerType)
+}
 
 func encodeBytesSlice(ctx *Context, b []byte, rv reflect.Value) ([]byte, error) {
 	return AppendBytes(b, rv.Bytes()), nil
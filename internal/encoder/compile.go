@@ -42,7 +42,7 @@ func compile(rt reflect.Type, seen seenMap) (encoder, error) {
 	switch {
 	case rt.Implements(marshalerType):
 		return compileMarshaler(rt)
-	case rt == bytesType:
+	case isBytesSlice(rt):
 		return encodeBytesSlice, nil
 	case rt.Kind() == reflect.Array && rt.Elem().Kind() == reflect.Uint8:
 		return compileBytesArray(rt)
@@ -1,6 +1,7 @@
 package encoder
 
 import (
+	"io"
 	"sync"
 	"unsafe"
 )
@@ -16,10 +17,22 @@ var ctxPool = sync.Pool{
 
 type empty struct{}
 
+// Options are the encoding settings carried by Context.
+type Options struct {
+	OmitDefaults bool // skip struct fields equal to their `default=...` option
+}
+
 type Context struct {
-	depth int
-	ptrSeen  map[unsafe.Pointer]empty
-	Buf      []byte
+	Options
+
+	depth   int
+	ptrSeen map[unsafe.Pointer]empty
+	Buf     []byte
+
+	// Writer and FlushThreshold enable progressive output,
+	// buffered data is written to Writer once it reaches FlushThreshold bytes.
+	Writer         io.Writer
+	FlushThreshold int
 }
 
 func NewCtx() *Context {
@@ -31,8 +44,25 @@ func FreeCtx(ctx *Context) {
 		return
 	}
 
+	ctx.Options = Options{}
 	ctx.depth = 0
+	ctx.Writer = nil
+	ctx.FlushThreshold = 0
 	clear(ctx.ptrSeen)
 	ctx.Buf = ctx.Buf[:0]
 	ctxPool.Put(ctx)
 }
+
+// flush writes b to ctx.Writer if it's large enough, and returns b truncated.
+func (ctx *Context) flush(b []byte) ([]byte, error) {
+	if ctx.Writer == nil || len(b) < ctx.FlushThreshold {
+		return b, nil
+	}
+
+	n, err := ctx.Writer.Write(b)
+	if err == nil && n != len(b) {
+		err = io.ErrShortWrite
+	}
+
+	return b[:0], err
+}
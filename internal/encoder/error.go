@@ -2,8 +2,16 @@ package encoder
 
 import (
 	"reflect"
+
+	"github.com/trim21/go-bencode/internal/errors"
 )
 
+// marshalError records the type of the failing value,
+// containers add their index/key to its path when the error is returned up.
+func marshalError(rt reflect.Type, err error) error {
+	return &errors.MarshalError{Type: rt, Err: err}
+}
+
 type UnsupportedTypeAsMapKeyError struct {
 	Type reflect.Type
 }
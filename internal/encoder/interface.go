@@ -16,7 +16,7 @@ LOOP:
 		switch rv.Kind() {
 		case reflect.Pointer, reflect.Interface:
 			if rv.IsNil() || rv.IsZero() {
-				return b, ErrNilValue
+				return b, marshalError(rv.Type(), ErrNilValue)
 			}
 			rv = rv.Elem()
 		default:
@@ -38,7 +38,7 @@ LOOP:
 
 	enc, err := compileWithCache(rv.Type())
 	if err != nil {
-		return nil, err
+		return nil, marshalError(rv.Type(), err)
 	}
 	return enc(ctx, b, rv)
 }
@@ -2,10 +2,11 @@ package encoder
 
 import (
 	"bytes"
-	"fmt"
 	"reflect"
 	"slices"
 	"strings"
+
+	"github.com/trim21/go-bencode/internal/errors"
 )
 
 const startDetectingCyclesAfter = 1000
@@ -54,7 +55,7 @@ func compileMap(rt reflect.Type, seen seenMap) (encoder, error) {
 		if ctx.depth++; ctx.depth > startDetectingCyclesAfter {
 			ptr := rv.UnsafePointer()
 			if _, ok := ctx.ptrSeen[ptr]; ok {
-				return b, fmt.Errorf("bencode: encountered a cycle via %s", rv.Type())
+				return b, marshalError(rv.Type(), ErrCycle)
 			}
 			ctx.ptrSeen[ptr] = empty{}
 			defer delete(ctx.ptrSeen, ptr)
@@ -73,6 +74,11 @@ func compileMap(rt reflect.Type, seen seenMap) (encoder, error) {
 			}
 
 			b, err = valueEncoder(ctx, b, rv.MapIndex(key))
+			if err != nil {
+				return b, errors.WithPath(err, errors.KeyElement(mapKeyString(key)))
+			}
+
+			b, err = ctx.flush(b)
 			if err != nil {
 				return b, err
 			}
@@ -92,6 +98,14 @@ func arrayByteKeyCompare(a reflect.Value, b reflect.Value) int {
 	return bytes.Compare(byteArrayToBytes(a), byteArrayToBytes(b))
 }
 
+func mapKeyString(key reflect.Value) string {
+	if key.Kind() == reflect.String {
+		return key.String()
+	}
+
+	return string(byteArrayToBytes(key))
+}
+
 func appendEmptyMap(b []byte) []byte {
 	return append(b, "de"...)
 }
@@ -1,24 +1,37 @@
 package encoder
 
 import (
-	"errors"
+	stderrors "errors"
 	"reflect"
+
+	"github.com/trim21/go-bencode/internal/errors"
 )
 
-var ErrNilValue = errors.New("bencode: nil values cannot be encoded")
+var ErrNilValue = stderrors.New("bencode: nil values cannot be encoded")
 
 func MarshalCtx(ctx *Context, v any) error {
+	var err error
+	ctx.Buf, err = AppendValue(ctx, ctx.Buf, v)
+
+	return err
+}
+
+// AppendValue appends encoded v to b.
+func AppendValue(ctx *Context, b []byte, v any) ([]byte, error) {
 	rv := reflect.ValueOf(v)
 	if !rv.IsValid() {
-		return ErrNilValue
+		return b, marshalError(nil, ErrNilValue)
 	}
 
 	enc, err := compileWithCache(rv.Type())
 	if err != nil {
-		return err
+		return b, err
 	}
 
-	ctx.Buf, err = enc(ctx, ctx.Buf, rv)
+	b, err = enc(ctx, b, rv)
+	if err != nil {
+		return b, errors.FinishPath(err)
+	}
 
-	return err
+	return b, nil
 }
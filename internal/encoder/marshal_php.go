@@ -7,6 +7,8 @@ import (
 
 var marshalerType = reflect.TypeFor[Marshaler]()
 
+var ErrMarshalerEmpty = errors.New("bencode: bencode.Marshaler return empty bytes")
+
 type Marshaler interface {
 	MarshalBencode() ([]byte, error)
 }
@@ -15,11 +17,11 @@ func compileMarshaler(rt reflect.Type) (encoder, error) {
 	return func(ctx *Context, b []byte, rv reflect.Value) ([]byte, error) {
 		raw, err := rv.Interface().(Marshaler).MarshalBencode()
 		if err != nil {
-			return nil, err
+			return nil, marshalError(rt, err)
 		}
 
 		if len(raw) == 0 {
-			return nil, errors.New("bencode: bencode.Marshaler return empty bytes")
+			return nil, marshalError(rt, ErrMarshalerEmpty)
 		}
 
 		return append(b, raw...), nil
@@ -0,0 +1,49 @@
+package encoder
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/trim21/go-bencode/internal/errors"
+)
+
+// compilePacked compiles encoder for struct field with `packed` option,
+// `[][N]byte` is encoded as a single bencode string of all elements concatenated.
+func compilePacked(rt reflect.Type, seen seenMap) (encoder, error) {
+	if rt.Kind() == reflect.Pointer {
+		inner, err := compilePacked(rt.Elem(), seen)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(ctx *Context, b []byte, rv reflect.Value) ([]byte, error) {
+			// nil is encoded like an empty slice, struct fields skip it before reaching here.
+			if rv.IsNil() {
+				return append(b, "0:"...), nil
+			}
+
+			return inner(ctx, b, rv.Elem())
+		}, nil
+	}
+
+	if rt.Kind() != reflect.Slice || rt.Elem().Kind() != reflect.Array ||
+		rt.Elem().Elem().Kind() != reflect.Uint8 || rt.Elem().Len() == 0 {
+		return nil, errors.ErrUnsupportedType(rt, "packed option requires a slice of non-empty byte arrays")
+	}
+
+	size := rt.Elem().Len()
+
+	return func(ctx *Context, b []byte, rv reflect.Value) ([]byte, error) {
+		length := rv.Len()
+
+		b = strconv.AppendInt(b, int64(length*size), 10)
+		b = append(b, ':')
+
+		// slice elements are always addressable
+		for i := range length {
+			b = append(b, rv.Index(i).Bytes()...)
+		}
+
+		return b, nil
+	}, nil
+}
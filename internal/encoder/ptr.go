@@ -21,14 +21,14 @@ func compilePtr(rt reflect.Type, seen seenMap) (encoder, error) {
 
 	return func(ctx *Context, b []byte, rv reflect.Value) ([]byte, error) {
 		if rv.IsNil() {
-			return b, ErrNilPtr
+			return b, marshalError(rv.Type(), ErrNilPtr)
 		}
 
 		if elemStruct {
 			if ctx.depth++; ctx.depth > startDetectingCyclesAfter {
 				ptr := rv.UnsafePointer()
 				if _, ok := ctx.ptrSeen[ptr]; ok {
-					return b, fmt.Errorf("bencode: encountered a cycle via %s", rv.Type())
+					return b, marshalError(rv.Type(), ErrCycle)
 				}
 				ctx.ptrSeen[ptr] = empty{}
 				defer delete(ctx.ptrSeen, ptr)
@@ -47,3 +47,5 @@ func compilePtr(rt reflect.Type, seen seenMap) (encoder, error) {
 }
 
 var ErrNilPtr = errors.New("bencode: bencode doesn't have a nil type, nil ptr can't be encoded")
+
+var ErrCycle = errors.New("bencode: encountered a cycle")
@@ -1,15 +1,16 @@
 package encoder
 
 import (
-	"fmt"
 	"reflect"
+
+	"github.com/trim21/go-bencode/internal/errors"
 )
 
 func compileSlice(rt reflect.Type, seen seenMap) (encoder, error) {
 	var enc encoder
 	var err error
 
-	if rt == bytesType {
+	if isBytesSlice(rt) {
 		return encodeBytesSlice, nil
 	}
 
@@ -26,7 +27,7 @@ func compileSlice(rt reflect.Type, seen seenMap) (encoder, error) {
 		if ctx.depth++; ctx.depth > startDetectingCyclesAfter {
 			ptr := rv.UnsafePointer()
 			if _, ok := ctx.ptrSeen[ptr]; ok {
-				return b, fmt.Errorf("bencode: encountered a cycle via %s", rv.Type())
+				return b, marshalError(rv.Type(), ErrCycle)
 			}
 			ctx.ptrSeen[ptr] = empty{}
 			defer delete(ctx.ptrSeen, ptr)
@@ -39,6 +40,11 @@ func compileSlice(rt reflect.Type, seen seenMap) (encoder, error) {
 		var err error // create a new error value, so shadow compiler's error
 		for i := 0; i < length; i++ {
 			b, err = enc(ctx, b, rv.Index(i))
+			if err != nil {
+				return b, errors.WithPath(err, errors.IndexElement(i))
+			}
+
+			b, err = ctx.flush(b)
 			if err != nil {
 				return b, err
 			}
@@ -0,0 +1,66 @@
+package encoder
+
+import (
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// compileStringOption compiles encoder for struct field with `string` option,
+// ints, uints, bools and big.Int are encoded as bencode strings of their decimal value.
+// Other types ignore the option.
+func compileStringOption(rt reflect.Type, seen seenMap) (encoder, error) {
+	switch {
+	case rt.Implements(marshalerType):
+		return compile(rt, seen)
+	case rt == typeBigInt:
+		return encodeBigIntString, nil
+	case rt == typeBigIntPtr:
+		return encodeBigIntPtrString, nil
+	}
+
+	switch rt.Kind() {
+	case reflect.Bool:
+		return encodeBoolString, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Int:
+		return encodeIntString, nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+		return encodeUintString, nil
+	}
+
+	return compile(rt, seen)
+}
+
+func encodeIntString(ctx *Context, b []byte, rv reflect.Value) ([]byte, error) {
+	var buf [20]byte
+	return AppendBytes(b, strconv.AppendInt(buf[:0], rv.Int(), 10)), nil
+}
+
+func encodeUintString(ctx *Context, b []byte, rv reflect.Value) ([]byte, error) {
+	var buf [20]byte
+	return AppendBytes(b, strconv.AppendUint(buf[:0], rv.Uint(), 10)), nil
+}
+
+func encodeBoolString(ctx *Context, b []byte, rv reflect.Value) ([]byte, error) {
+	if rv.Bool() {
+		return append(b, "1:1"...), nil
+	}
+
+	return append(b, "1:0"...), nil
+}
+
+func encodeBigIntString(ctx *Context, b []byte, rv reflect.Value) ([]byte, error) {
+	v := rv.Interface().(big.Int)
+
+	return AppendStr(b, v.String()), nil
+}
+
+func encodeBigIntPtrString(ctx *Context, b []byte, rv reflect.Value) ([]byte, error) {
+	v := rv.Interface().(*big.Int)
+
+	if v == nil {
+		return append(b, "1:0"...), nil
+	}
+
+	return AppendStr(b, v.String()), nil
+}
@@ -1,11 +1,13 @@
 package encoder
 
 import (
+	"bytes"
 	"fmt"
 	"reflect"
 	"slices"
 	"strings"
 
+	"github.com/trim21/go-bencode/internal/errors"
 	"github.com/trim21/go-bencode/internal/runtime"
 )
 
@@ -16,8 +18,15 @@ type structEncoder struct {
 	encode    encoder
 	fieldName string // field fieldName
 	omitEmpty bool
+	omitZero  bool
 	// support for Anonymous struct
 	isZero func(reflect.Value) bool
+	// for omitzero, uses IsZero method if type has one
+	isZeroMethod func(reflect.Value) bool
+	// reports if value equals to `default=...` option, nil if field doesn't have default
+	isDefault func(reflect.Value) bool
+	// map field with `inline` option, encode is the encoder of map value
+	inline bool
 }
 
 type seenMap = map[reflect.Type]*structRecEncoder
@@ -41,7 +50,13 @@ func compileStruct(rt reflect.Type, seen seenMap) (encoder, error) {
 
 	seen[rt] = typeEncoder
 
-	enc, err := compileStructFields(rt, seen)
+	var enc encoder
+	var err error
+	if runtime.StructOptionsFromType(rt).Tuple {
+		enc, err = compileTuple(rt, seen)
+	} else {
+		enc, err = compileStructFields(rt, seen)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -61,6 +76,21 @@ func compileStructFields(rt reflect.Type, seen seenMap) (encoder, error) {
 		return nil, err
 	}
 
+	var inline *structEncoder
+	fields = slices.DeleteFunc(fields, func(field structEncoder) bool {
+		if field.inline && inline == nil {
+			inline = &field
+			return true
+		}
+		return false
+	})
+
+	for _, field := range fields {
+		if field.inline {
+			return nil, errors.ErrUnsupportedType(rt, "found multiple inline fields")
+		}
+	}
+
 	slices.SortFunc(fields, func(a, b structEncoder) int {
 		return strings.Compare(a.fieldName, b.fieldName)
 	})
@@ -74,7 +104,7 @@ func compileStructFields(rt reflect.Type, seen seenMap) (encoder, error) {
 		fieldNames[field.fieldName] = true
 	}
 
-	if len(fields) == 0 {
+	if len(fields) == 0 && inline == nil {
 		return func(ctx *Context, b []byte, rv reflect.Value) ([]byte, error) {
 			return appendEmptyMap(b), nil
 		}, nil
@@ -84,9 +114,23 @@ func compileStructFields(rt reflect.Type, seen seenMap) (encoder, error) {
 		// shadow compiler's error
 		var err error
 
+		var extra inlineEntries
+		if inline != nil {
+			extra, err = newInlineEntries(rv, inline, fieldNames)
+			if err != nil {
+				return b, err
+			}
+		}
+
 		b = append(b, 'd')
 
 		for _, field := range fields {
+			// entries of inline map are merged in key order.
+			b, err = extra.encodeBefore(ctx, b, field.fieldName)
+			if err != nil {
+				return b, err
+			}
+
 			v := rv
 			for _, index := range field.fieldIndex {
 				v = v.Field(index)
@@ -98,19 +142,108 @@ func compileStructFields(rt reflect.Type, seen seenMap) (encoder, error) {
 				}
 			}
 
+			if field.omitZero {
+				if field.isZeroMethod(v) {
+					continue
+				}
+			}
+
+			if ctx.OmitDefaults && field.isDefault != nil {
+				if field.isDefault(v) {
+					continue
+				}
+			}
+
 			b, err = field.encode(ctx, b, v)
+			if err != nil {
+				return b, errors.WithPath(err, errors.KeyElement(field.fieldName))
+			}
+
+			b, err = ctx.flush(b)
 			if err != nil {
 				return b, err
 			}
 		}
 
+		b, err = extra.encodeBefore(ctx, b, "")
+		if err != nil {
+			return b, err
+		}
+
 		return append(b, 'e'), nil
 	}, nil
 }
 
-func compileStructField(rt reflect.Type, fieldName string, seen seenMap) (encoder, error) {
+// inlineEntries are the sorted entries of a map field with `inline` option, not encoded yet.
+type inlineEntries struct {
+	m      reflect.Value
+	keys   []reflect.Value
+	encode encoder
+}
+
+func newInlineEntries(rv reflect.Value, inline *structEncoder, fieldNames map[string]bool) (inlineEntries, error) {
+	m := rv
+	for _, index := range inline.fieldIndex {
+		m = m.Field(index)
+	}
+
+	if m.Len() == 0 {
+		return inlineEntries{}, nil
+	}
+
+	keys := m.MapKeys()
+	slices.SortFunc(keys, stringKeyCompare)
+
+	for _, key := range keys {
+		if fieldNames[key.String()] {
+			err := marshalError(m.Type(), fmt.Errorf("bencode: inline key %q conflicts with struct field", key.String()))
+			return inlineEntries{}, errors.WithPath(err, errors.KeyElement(key.String()))
+		}
+	}
+
+	return inlineEntries{m: m, keys: keys, encode: inline.encode}, nil
+}
+
+// encodeBefore encodes entries with key less than fieldName, or all remaining entries if fieldName is empty.
+func (e *inlineEntries) encodeBefore(ctx *Context, b []byte, fieldName string) ([]byte, error) {
+	var err error
+	for len(e.keys) != 0 {
+		key := e.keys[0]
+		if fieldName != "" && key.String() >= fieldName {
+			break
+		}
+		e.keys = e.keys[1:]
+
+		b = AppendStr(b, key.String())
+		b, err = e.encode(ctx, b, e.m.MapIndex(key))
+		if err != nil {
+			return b, errors.WithPath(err, errors.KeyElement(key.String()))
+		}
+
+		b, err = ctx.flush(b)
+		if err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+// fieldValueCompiler returns the compiler of field value for field options `string` and `packed`.
+func fieldValueCompiler(cfg *runtime.StructTag) func(reflect.Type, seenMap) (encoder, error) {
+	switch {
+	case cfg.IsPacked:
+		return compilePacked
+	case cfg.IsString:
+		return compileStringOption
+	}
+
+	return compile
+}
+
+func compileStructField(rt reflect.Type, fieldName string, compileValue func(reflect.Type, seenMap) (encoder, error), seen seenMap) (encoder, error) {
 	if rt.Kind() != reflect.Pointer {
-		inner, err := compile(rt, seen)
+		inner, err := compileValue(rt, seen)
 		if err != nil {
 			return nil, err
 		}
@@ -124,7 +257,7 @@ func compileStructField(rt reflect.Type, fieldName string, seen seenMap) (encode
 		return nil, fmt.Errorf("bencode: nested ptr is not supported %s", rt.String())
 	}
 
-	inner, err := compile(rt.Elem(), seen)
+	inner, err := compileValue(rt.Elem(), seen)
 	if err != nil {
 		return nil, err
 	}
@@ -142,7 +275,7 @@ func compileStructField(rt reflect.Type, fieldName string, seen seenMap) (encode
 			if ctx.depth++; ctx.depth > startDetectingCyclesAfter {
 				ptr := rv.UnsafePointer()
 				if _, ok := ctx.ptrSeen[ptr]; ok {
-					return b, fmt.Errorf("bencode: encountered a cycle via %s", rv.Type())
+					return b, marshalError(rv.Type(), ErrCycle)
 				}
 				ctx.ptrSeen[ptr] = empty{}
 				defer delete(ctx.ptrSeen, ptr)
@@ -192,6 +325,10 @@ func compileStructFieldsEncoder(ft reflect.StructField, fieldIndex []int, index
 		}
 
 		if ft.Tag.Get("bencode") == "" {
+			if runtime.StructOptionsFromType(rt).Tuple {
+				return nil, errors.ErrUnsupportedType(rt, "tuple struct can't be embedded")
+			}
+
 			for ni := 0; ni < rt.NumField(); ni++ {
 				nField := rt.Field(ni)
 				enc, err := compileStructFieldsEncoder(nField, append(slices.Clone(fieldIndex), index), ni, seen)
@@ -205,17 +342,51 @@ func compileStructFieldsEncoder(ft reflect.StructField, fieldIndex []int, index
 		}
 	}
 
-	fieldEncoder, err := compileStructField(rt, cfg.Name(), seen)
+	if cfg.IsInline {
+		if rt.Kind() != reflect.Map || rt.Key().Kind() != reflect.String {
+			return nil, errors.ErrUnsupportedType(rt, fmt.Sprintf("inline field %s must be a map with string key", ft.Name))
+		}
+
+		valueEncoder, err := compile(rt.Elem(), seen)
+		if err != nil {
+			return nil, err
+		}
+
+		return []structEncoder{{
+			fieldIndex: append(slices.Clone(fieldIndex), index),
+			encode:     valueEncoder,
+			fieldName:  cfg.Name(),
+			inline:     true,
+		}}, nil
+	}
+
+	fieldEncoder, err := compileStructField(rt, cfg.Name(), fieldValueCompiler(cfg), seen)
 	if err != nil {
 		return nil, err
 	}
 
+	var isDefault func(reflect.Value) bool
+	if cfg.HasDefault {
+		isDefault, err = compileIsDefault(rt, cfg.Default)
+		if err != nil {
+			return nil, errors.ErrUnsupportedType(rt, fmt.Sprintf("field %s: %s", ft.Name, err))
+		}
+	}
+
+	var isZeroMethod func(reflect.Value) bool
+	if cfg.IsOmitZero {
+		isZeroMethod = compileIsZero(ft.Type)
+	}
+
 	encoders = append(encoders, structEncoder{
-		fieldIndex: append(slices.Clone(fieldIndex), index),
-		encode:     fieldEncoder,
-		fieldName:  cfg.Name(),
-		isZero:     compileIsZero(ft.Type),
-		omitEmpty:  cfg.IsOmitEmpty,
+		fieldIndex:   append(slices.Clone(fieldIndex), index),
+		encode:       fieldEncoder,
+		fieldName:    cfg.Name(),
+		isZero:       compileIsEmpty(ft.Type),
+		isZeroMethod: isZeroMethod,
+		isDefault:    isDefault,
+		omitEmpty:    cfg.IsOmitEmpty,
+		omitZero:     cfg.IsOmitZero,
 	})
 
 	return encoders, nil
@@ -227,14 +398,82 @@ type IsZeroValue interface {
 
 var isZeroValueType = reflect.TypeFor[IsZeroValue]()
 
-func compileIsZero(rt reflect.Type) func(rv reflect.Value) bool {
+// compileIsEmpty compiles check of `omitempty` option,
+// it's true for zero values and empty strings, slices and maps.
+func compileIsEmpty(rt reflect.Type) func(rv reflect.Value) bool {
 	if rt.Implements(isZeroValueType) {
 		return func(rv reflect.Value) bool {
 			return rv.Interface().(IsZeroValue).IsZeroBencodeValue()
 		}
 	}
 
+	switch rt.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return func(rv reflect.Value) bool {
+			return rv.Len() == 0
+		}
+	}
+
+	return func(rv reflect.Value) bool {
+		return rv.IsZero()
+	}
+}
+
+type isZeroer interface {
+	IsZero() bool
+}
+
+var isZeroerType = reflect.TypeFor[isZeroer]()
+
+// compileIsZero compiles check of `omitzero` option,
+// it uses IsZero method if rt has one, with value or pointer receiver.
+func compileIsZero(rt reflect.Type) func(rv reflect.Value) bool {
+	switch {
+	case rt.Kind() == reflect.Pointer && rt.Implements(isZeroerType):
+		return func(rv reflect.Value) bool {
+			return rv.IsNil() || rv.Interface().(isZeroer).IsZero()
+		}
+	case rt.Kind() == reflect.Interface && rt.Implements(isZeroerType):
+		return func(rv reflect.Value) bool {
+			return rv.IsNil() ||
+				(rv.Elem().Kind() == reflect.Pointer && rv.Elem().IsNil()) ||
+				rv.Interface().(isZeroer).IsZero()
+		}
+	case rt.Implements(isZeroerType):
+		return func(rv reflect.Value) bool {
+			return rv.Interface().(isZeroer).IsZero()
+		}
+	case reflect.PointerTo(rt).Implements(isZeroerType):
+		return func(rv reflect.Value) bool {
+			if !rv.CanAddr() {
+				// value of a struct passed by value, copy it to call the method.
+				v := reflect.New(rt).Elem()
+				v.Set(rv)
+				rv = v
+			}
+
+			return rv.Addr().Interface().(isZeroer).IsZero()
+		}
+	}
+
 	return func(rv reflect.Value) bool {
 		return rv.IsZero()
 	}
 }
+
+func compileIsDefault(rt reflect.Type, raw string) (func(rv reflect.Value) bool, error) {
+	def, err := runtime.ParseDefault(rt, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if rt.Kind() == reflect.Slice {
+		return func(rv reflect.Value) bool {
+			return bytes.Equal(rv.Bytes(), def.Bytes())
+		}, nil
+	}
+
+	return func(rv reflect.Value) bool {
+		return rv.Equal(def)
+	}, nil
+}
@@ -0,0 +1,54 @@
+package encoder
+
+import (
+	"reflect"
+
+	"github.com/trim21/go-bencode/internal/errors"
+	"github.com/trim21/go-bencode/internal/runtime"
+)
+
+// compileTuple compiles encoder of struct with `tuple` option,
+// it's encoded as a list of its fields in declaration order.
+func compileTuple(rt reflect.Type, seen seenMap) (encoder, error) {
+	type tupleField struct {
+		index  int
+		encode encoder
+	}
+
+	var fields []tupleField
+
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		cfg := runtime.StructTagFromField(field)
+		if cfg.Key == "-" || !field.IsExported() {
+			continue
+		}
+
+		enc, err := fieldValueCompiler(cfg)(field.Type, seen)
+		if err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, tupleField{index: i, encode: enc})
+	}
+
+	return func(ctx *Context, b []byte, rv reflect.Value) ([]byte, error) {
+		var err error
+
+		b = append(b, 'l')
+
+		for i, field := range fields {
+			b, err = field.encode(ctx, b, rv.Field(field.index))
+			if err != nil {
+				return b, errors.WithPath(err, errors.IndexElement(i))
+			}
+
+			b, err = ctx.flush(b)
+			if err != nil {
+				return b, err
+			}
+		}
+
+		return append(b, 'e'), nil
+	}, nil
+}
@@ -3,6 +3,8 @@ package errors
 import (
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 )
 
 type InvalidUnmarshalError struct {
@@ -24,14 +26,21 @@ func (e *InvalidUnmarshalError) Error() string {
 type SyntaxError struct {
 	msg    string // description of error
 	Offset int    // error occurred after reading Offset bytes
+	Path   Path   // location of the value containing the error
+	rev    Path   // elements added by WithPath, from the leaf up
 }
 
 func (e *SyntaxError) Error() string {
+	msg := e.msg
+	if len(e.Path) != 0 {
+		msg = fmt.Sprintf("%s at %s", msg, e.Path)
+	}
+
 	if e.Offset != 0 {
-		return fmt.Sprintf("%s: index %d", e.msg, e.Offset)
+		return fmt.Sprintf("%s: index %d", msg, e.Offset)
 	}
 
-	return e.msg
+	return msg
 }
 
 // An UnmarshalTypeError describes a JSON value that was
@@ -42,36 +51,164 @@ type UnmarshalTypeError struct {
 	Offset int          // error occurred after reading Offset bytes
 	Struct string       // name of the struct type containing the field
 	Field  string       // the full path from root node to the field
+	Path   Path         // location of the value in bencode document
+	rev    Path         // elements added by WithPath, from the leaf up
+	msg    string       // optional description replacing the default message
 }
 
 func (e *UnmarshalTypeError) Error() string {
+	var at string
+	if len(e.Path) != 0 {
+		at = " at " + e.Path.String()
+	}
+
+	if e.msg != "" {
+		return fmt.Sprintf("%s%s (offset %d)", e.msg, at, e.Offset)
+	}
 	if e.Struct != "" || e.Field != "" {
-		return fmt.Sprintf("bencode: cannot unmarshal %s into Go struct field %s.%s of type %s (offset %d)",
-			e.Value, e.Struct, e.Field, e.Type, e.Offset,
+		return fmt.Sprintf("bencode: cannot unmarshal %s into Go struct field %s.%s of type %s%s (offset %d)",
+			e.Value, e.Struct, e.Field, e.Type, at, e.Offset,
 		)
 	}
-	return fmt.Sprintf("bencode: cannot unmarshal %s into Go value of type %s (offset: %d)", e.Value, e.Type, e.Offset)
+	return fmt.Sprintf("bencode: cannot unmarshal %s into Go value of type %s%s (offset: %d)", e.Value, e.Type, at, e.Offset)
+}
+
+// A LimitError is returned when input exceeds a decoding limit.
+type LimitError struct {
+	Limit  string // name of the limit, like "MaxDepth" or "MaxListLength"
+	Max    int64  // value of the limit
+	Offset int    // offset of the value exceeding the limit
+	Path   Path   // location of the value in bencode document
+	rev    Path   // elements added by WithPath, from the leaf up
+}
+
+func (e *LimitError) Error() string {
+	if len(e.Path) != 0 {
+		return fmt.Sprintf("bencode: exceeded %s %d at %s (offset %d)", e.Limit, e.Max, e.Path, e.Offset)
+	}
+	return fmt.Sprintf("bencode: exceeded %s %d (offset %d)", e.Limit, e.Max, e.Offset)
+}
+
+// An UnknownFieldError is returned for a dictionary key without matching struct field,
+// when unknown fields are disallowed.
+type UnknownFieldError struct {
+	Key    string       // the unknown dictionary key
+	Type   reflect.Type // struct type being decoded
+	Offset int          // offset of the key
+	Path   Path         // location of the unknown entry in bencode document
+	rev    Path         // elements added by WithPath, from the leaf up
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("bencode: unknown field %q for Go struct %s at %s (offset %d)", e.Key, e.Type, e.Path, e.Offset)
+}
+
+// A MissingFieldsError is returned when a dictionary doesn't have all keys of `required` struct fields.
+type MissingFieldsError struct {
+	Keys   []string     // missing keys, in sorted order
+	Type   reflect.Type // struct type being decoded
+	Offset int          // offset of the dictionary
+	Path   Path         // location of the dictionary in bencode document
+	rev    Path         // elements added by WithPath, from the leaf up
+}
+
+func (e *MissingFieldsError) Error() string {
+	keys := make([]string, len(e.Keys))
+	for i, key := range e.Keys {
+		keys[i] = strconv.Quote(key)
+	}
+
+	var at string
+	if len(e.Path) != 0 {
+		at = " at " + e.Path.String()
+	}
+
+	return fmt.Sprintf("bencode: missing required fields %s for Go struct %s%s (offset %d)", strings.Join(keys, ", "), e.Type, at, e.Offset)
+}
+
+// UnmarshalErrors is returned when type errors are collected instead of stopping at the first one.
+type UnmarshalErrors struct {
+	Errors []error
+}
+
+func (e *UnmarshalErrors) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "bencode: %d errors when decoding value", len(e.Errors))
+	for _, err := range e.Errors {
+		sb.WriteString("\n\t")
+		sb.WriteString(err.Error())
+	}
+
+	return sb.String()
+}
+
+func (e *UnmarshalErrors) Unwrap() []error {
+	return e.Errors
 }
 
 // An UnsupportedTypeError is returned by Marshal when attempting
 // to encode an unsupported value type.
 type UnsupportedTypeError struct {
-	Type reflect.Type
+	Type   reflect.Type
+	reason string
 }
 
 func (e *UnsupportedTypeError) Error() string {
+	if e.reason != "" {
+		return fmt.Sprintf("bencode: unsupported type: %s, %s", e.Type, e.reason)
+	}
 	return fmt.Sprintf("bencode: unsupported type: %s", e.Type)
 }
 
+// A MarshalError describes a Go value that can't be encoded.
+type MarshalError struct {
+	Type reflect.Type // type of the failing value, nil for nil interface
+	Path Path         // struct field, map key and slice index from the top-level value
+	rev  Path         // elements added by WithPath, from the leaf up
+	Err  error
+}
+
+func (e *MarshalError) Error() string {
+	if len(e.Path) != 0 {
+		return fmt.Sprintf("%s (type %s at %s)", e.Err, e.Type, e.Path)
+	}
+	return fmt.Sprintf("%s (type %s)", e.Err, e.Type)
+}
+
+func (e *MarshalError) Unwrap() error {
+	return e.Err
+}
+
+// An UnmarshalerError is returned when UnmarshalBencode method of a type returns an error.
+type UnmarshalerError struct {
+	Type   reflect.Type
+	Offset int  // offset of the value passed to UnmarshalBencode
+	Path   Path // location of the value in bencode document
+	rev    Path // elements added by WithPath, from the leaf up
+	Err    error
+}
+
+func (e *UnmarshalerError) Error() string {
+	if len(e.Path) != 0 {
+		return fmt.Sprintf("bencode: error calling UnmarshalBencode for type %s at %s (offset %d): %s", e.Type, e.Path, e.Offset, e.Err)
+	}
+	return fmt.Sprintf("bencode: error calling UnmarshalBencode for type %s (offset %d): %s", e.Type, e.Offset, e.Err)
+}
+
+func (e *UnmarshalerError) Unwrap() error {
+	return e.Err
+}
+
 func ErrSyntax(msg string, offset int) *SyntaxError {
 	return &SyntaxError{msg: msg, Offset: offset}
 }
 
-func ErrExceededMaxDepth(c byte, cursor int) *SyntaxError {
-	return &SyntaxError{
-		msg:    fmt.Sprintf(`invalid character "%c" exceeded max depth`, c),
-		Offset: cursor,
-	}
+func ErrExceededMaxDepth(max int64, cursor int) *LimitError {
+	return ErrLimit("MaxDepth", max, cursor)
+}
+
+func ErrLimit(limit string, max int64, cursor int) *LimitError {
+	return &LimitError{Limit: limit, Max: max, Offset: cursor}
 }
 
 func ErrUnexpectedEnd(msg string, cursor int) *SyntaxError {
@@ -81,11 +218,14 @@ func ErrUnexpectedEnd(msg string, cursor int) *SyntaxError {
 	}
 }
 
-func ErrExpecting(msg string, buf []byte, cursor int) error {
+func ErrExpecting(msg string, buf []byte, cursor int) *SyntaxError {
 	if cursor >= len(buf) {
 		return ErrUnexpectedEnd(msg, cursor)
 	}
-	return fmt.Errorf("bencode: expecting start of %s, found '%c' instead. index %d", msg, buf[cursor], cursor)
+	return &SyntaxError{
+		msg:    fmt.Sprintf("bencode: expecting start of %s, found '%c' instead", msg, buf[cursor]),
+		Offset: cursor,
+	}
 }
 
 func ErrInvalidCharacter(c byte, context string, cursor int) *SyntaxError {
@@ -108,24 +248,33 @@ func ErrInvalidBeginningOfValue(c byte, cursor int) *SyntaxError {
 	}
 }
 
-func ErrValueOverflow(v any, t string) error {
-	return &overflowError{
-		v: v,
-		t: t,
+func ErrValueOverflow(v []byte, rt reflect.Type, cursor int) *UnmarshalTypeError {
+	return &UnmarshalTypeError{
+		Value:  "number " + string(v),
+		Type:   rt,
+		Offset: cursor,
 	}
 }
 
-type overflowError struct {
-	t string
-	v any
+func ErrTypeMismatch(msg string, rt reflect.Type, cursor int) *UnmarshalTypeError {
+	return &UnmarshalTypeError{
+		Type:   rt,
+		Offset: cursor,
+		msg:    msg,
+	}
 }
 
-func (o overflowError) Error() string {
-	return fmt.Sprintf("bencode: %v overflow type %s", o.v, o.t)
+func ErrUnsupportedType(rt reflect.Type, reason string) *UnsupportedTypeError {
+	return &UnsupportedTypeError{Type: rt, reason: reason}
 }
 
-func DataTooShort() error {
+func ErrEmptyData() *SyntaxError {
+	return &SyntaxError{msg: "bencode: empty data"}
+}
+
+func DataTooShort(cursor int) *SyntaxError {
 	return &SyntaxError{
-		msg: "bencode: unexpected end of bencode input",
+		msg:    "bencode: unexpected end of bencode input",
+		Offset: cursor,
 	}
 }
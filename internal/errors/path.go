@@ -0,0 +1,180 @@
+package errors
+
+import (
+	stderrors "errors"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PathElement is a dictionary key or a list index.
+type PathElement struct {
+	Key     string // dictionary key, only used if IsIndex is false
+	Index   int    // list index, only used if IsIndex is true
+	IsIndex bool
+}
+
+func KeyElement(key string) PathElement {
+	return PathElement{Key: key}
+}
+
+func IndexElement(index int) PathElement {
+	return PathElement{Index: index, IsIndex: true}
+}
+
+// Path is the location of a value in a bencode document, from the top-level value.
+type Path []PathElement
+
+// String renders path like `info.files[3].path[0]`.
+// Keys that can't be written plainly are quoted, like `info["name.utf-8"]`.
+func (p Path) String() string {
+	var sb strings.Builder
+
+	for i, e := range p {
+		switch {
+		case e.IsIndex:
+			sb.WriteByte('[')
+			sb.WriteString(strconv.Itoa(e.Index))
+			sb.WriteByte(']')
+		case !isPlainKey(e.Key):
+			sb.WriteByte('[')
+			sb.WriteString(strconv.Quote(e.Key))
+			sb.WriteByte(']')
+		default:
+			if i != 0 {
+				sb.WriteByte('.')
+			}
+			sb.WriteString(e.Key)
+		}
+	}
+
+	return sb.String()
+}
+
+func isPlainKey(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for _, c := range []byte(key) {
+		if c < 0x20 || c >= 0x7f {
+			return false
+		}
+
+		switch c {
+		case '.', '[', ']', '"', '\\':
+			return false
+		}
+	}
+
+	return true
+}
+
+type pathError interface {
+	appendPath(el PathElement)
+	finishPath()
+}
+
+// finishPath returns rev reversed followed by path,
+// elements of rev were added from the leaf up, after path was set.
+func finishPath(rev, path Path) Path {
+	if len(rev) == 0 {
+		return path
+	}
+
+	slices.Reverse(rev)
+	return append(rev, path...)
+}
+
+func (e *SyntaxError) appendPath(el PathElement) {
+	e.rev = append(e.rev, el)
+}
+
+func (e *SyntaxError) finishPath() {
+	e.Path = finishPath(e.rev, e.Path)
+	e.rev = nil
+}
+
+func (e *UnmarshalTypeError) appendPath(el PathElement) {
+	e.rev = append(e.rev, el)
+}
+
+func (e *UnmarshalTypeError) finishPath() {
+	e.Path = finishPath(e.rev, e.Path)
+	e.rev = nil
+}
+
+func (e *UnmarshalerError) appendPath(el PathElement) {
+	e.rev = append(e.rev, el)
+}
+
+func (e *UnmarshalerError) finishPath() {
+	e.Path = finishPath(e.rev, e.Path)
+	e.rev = nil
+}
+
+func (e *UnknownFieldError) appendPath(el PathElement) {
+	e.rev = append(e.rev, el)
+}
+
+func (e *UnknownFieldError) finishPath() {
+	e.Path = finishPath(e.rev, e.Path)
+	e.rev = nil
+}
+
+func (e *MissingFieldsError) appendPath(el PathElement) {
+	e.rev = append(e.rev, el)
+}
+
+func (e *MissingFieldsError) finishPath() {
+	e.Path = finishPath(e.rev, e.Path)
+	e.rev = nil
+}
+
+func (e *LimitError) appendPath(el PathElement) {
+	e.rev = append(e.rev, el)
+}
+
+func (e *LimitError) finishPath() {
+	e.Path = finishPath(e.rev, e.Path)
+	e.rev = nil
+}
+
+func (e *MarshalError) appendPath(el PathElement) {
+	e.rev = append(e.rev, el)
+}
+
+func (e *MarshalError) finishPath() {
+	e.Path = finishPath(e.rev, e.Path)
+	e.rev = nil
+}
+
+// WithPath adds el to the path of err, it's called by container encoders and decoders
+// when an element fails so the path is built from the leaf up to the top-level value.
+// Elements are appended as the error unwinds, [FinishPath] puts them in order.
+func WithPath(err error, el PathElement) error {
+	var e pathError
+	if stderrors.As(err, &e) {
+		e.appendPath(el)
+	}
+
+	return err
+}
+
+// FinishPath sets the Path of err, and of errors collected in an *UnmarshalErrors,
+// from the elements added by WithPath. It's called once when an error leaves the encoder or decoder.
+func FinishPath(err error) error {
+	var errs *UnmarshalErrors
+	if stderrors.As(err, &errs) {
+		for _, collected := range errs.Errors {
+			FinishPath(collected)
+		}
+	}
+
+	var e pathError
+	if stderrors.As(err, &e) {
+		e.finishPath()
+	}
+
+	return err
+}
@@ -0,0 +1,96 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+)
+
+// Offset returns the input offset carried by err or any error it wraps.
+func Offset(err error) (int, bool) {
+	var syntaxError *SyntaxError
+	if stderrors.As(err, &syntaxError) {
+		return syntaxError.Offset, true
+	}
+
+	var typeError *UnmarshalTypeError
+	if stderrors.As(err, &typeError) {
+		return typeError.Offset, true
+	}
+
+	var limitError *LimitError
+	if stderrors.As(err, &limitError) {
+		return limitError.Offset, true
+	}
+
+	var unknownFieldError *UnknownFieldError
+	if stderrors.As(err, &unknownFieldError) {
+		return unknownFieldError.Offset, true
+	}
+
+	var missingFieldsError *MissingFieldsError
+	if stderrors.As(err, &missingFieldsError) {
+		return missingFieldsError.Offset, true
+	}
+
+	var unmarshalerError *UnmarshalerError
+	if stderrors.As(err, &unmarshalerError) {
+		return unmarshalerError.Offset, true
+	}
+
+	return 0, false
+}
+
+const snippetWidth = 16
+
+// Snippet renders a hex dump of data around offset, with the byte at offset marked:
+//
+//	00000000  64 31 3a 61 69 31 65 31  3a 62 78 65              |d1:ai1e1:bxe|
+//	                                         ^^
+func Snippet(data []byte, offset int) string {
+	offset = max(0, min(offset, len(data)))
+
+	line := offset - offset%snippetWidth
+	start := max(0, line-snippetWidth)
+	end := min(len(data), line+2*snippetWidth)
+
+	var sb strings.Builder
+
+	for l := start; l < end || l == line; l += snippetWidth {
+		chunk := data[min(l, len(data)):min(l+snippetWidth, len(data))]
+
+		fmt.Fprintf(&sb, "%08x ", l)
+		for i := range snippetWidth {
+			if i == snippetWidth/2 {
+				sb.WriteByte(' ')
+			}
+
+			if i < len(chunk) {
+				fmt.Fprintf(&sb, " %02x", chunk[i])
+			} else {
+				sb.WriteString("   ")
+			}
+		}
+
+		sb.WriteString("  |")
+		for _, c := range chunk {
+			if c < 0x20 || c >= 0x7f {
+				c = '.'
+			}
+			sb.WriteByte(c)
+		}
+		sb.WriteString("|\n")
+
+		if l == line {
+			col := offset - line
+			pad := 10 + 3*col
+			if col >= snippetWidth/2 {
+				pad++
+			}
+			sb.WriteString(strings.Repeat(" ", pad))
+			sb.WriteString("^^\n")
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,45 @@
+package runtime
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ParseDefault parses value of `default=...` tag option as a value of type rt.
+// Only ints, uints, bools, strings and byte slices are supported.
+func ParseDefault(rt reflect.Type, s string) (reflect.Value, error) {
+	v := reflect.New(rt).Elem()
+
+	switch rt.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, rt.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid default value %q: %w", s, err)
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, rt.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid default value %q: %w", s, err)
+		}
+		v.SetUint(u)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid default value %q: %w", s, err)
+		}
+		v.SetBool(b)
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Slice:
+		if rt.Elem().Kind() != reflect.Uint8 {
+			return reflect.Value{}, fmt.Errorf("default value is not supported for %s", rt)
+		}
+		v.SetBytes([]byte(s))
+	default:
+		return reflect.Value{}, fmt.Errorf("default value is not supported for %s", rt)
+	}
+
+	return v, nil
+}
@@ -23,6 +23,14 @@ func IsIgnoredStructField(field reflect.StructField) bool {
 type StructTag struct {
 	Key         string
 	IsOmitEmpty bool
+	IsOmitZero  bool
+	IsRequired  bool
+	IsString    bool // encode numbers and bools as bencode strings
+	IsInline    bool // `inline` or `remain`, map field holding keys without matching struct field
+	IsPacked    bool // `[][N]byte` encoded as one concatenated bencode string
+	HasDefault  bool
+	Default     string   // raw value of `default=...` option
+	Aliases     []string // keys of `alias=...` options, in tag order
 	Field       reflect.StructField
 }
 
@@ -66,8 +74,56 @@ func StructTagFromField(field reflect.StructField) *StructTag {
 			switch opt {
 			case "omitempty":
 				st.IsOmitEmpty = true
+			case "omitzero":
+				st.IsOmitZero = true
+			case "required":
+				st.IsRequired = true
+			case "string":
+				st.IsString = true
+			case "inline", "remain":
+				st.IsInline = true
+			case "packed":
+				st.IsPacked = true
+			default:
+				if v, ok := strings.CutPrefix(opt, "default="); ok {
+					st.HasDefault = true
+					st.Default = v
+				} else if v, ok := strings.CutPrefix(opt, "alias="); ok && v != "" {
+					st.Aliases = append(st.Aliases, v)
+				}
 			}
 		}
 	}
 	return st
 }
+
+// StructOptions are options of a struct type, set by the tag of a blank field:
+//
+//	_ struct{} `bencode:",disallowunknown"`
+type StructOptions struct {
+	DisallowUnknownFields bool
+	Tuple                 bool // encoded as a list of fields in declaration order
+}
+
+func StructOptionsFromType(rt reflect.Type) StructOptions {
+	var o StructOptions
+
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		if field.Name != "_" {
+			continue
+		}
+
+		opts := strings.Split(getTag(field), ",")
+		for _, opt := range opts[1:] {
+			switch opt {
+			case "disallowunknown":
+				o.DisallowUnknownFields = true
+			case "tuple":
+				o.Tuple = true
+			}
+		}
+	}
+
+	return o
+}
@@ -11,6 +11,13 @@ type tagFixture struct {
 	Ignored    int `bencode:"-"`
 	Invalid    int `bencode:"bad\\tag"`
 	unexported int
+	Required   int            `bencode:"req,required"`
+	Aliased    int            `bencode:"name,alias=name.utf-8,alias=n"`
+	Inline     map[string]any `bencode:",inline"`
+	Remain     map[string]any `bencode:",remain"`
+	Quoted     int            `bencode:"quoted,string"`
+	Zero       int            `bencode:"zero,omitzero"`
+	Packed     [][20]byte     `bencode:"pieces,packed"`
 }
 
 func TestStructFieldMetadata(t *testing.T) {
@@ -42,6 +49,40 @@ func TestStructFieldMetadata(t *testing.T) {
 		t.Fatal("omitempty option was not detected")
 	}
 
+	if customTag.IsRequired {
+		t.Fatal("required option was detected without being set")
+	}
+	if requiredTag := StructTagFromField(rt.Field(5)); !requiredTag.IsRequired {
+		t.Fatal("required option was not detected")
+	}
+
+	aliasedTag := StructTagFromField(rt.Field(6))
+	if got := aliasedTag.Name(); got != "name" {
+		t.Fatalf("aliased field name = %q, want %q", got, "name")
+	}
+	if !reflect.DeepEqual(aliasedTag.Aliases, []string{"name.utf-8", "n"}) {
+		t.Fatalf("aliases = %q, want %q", aliasedTag.Aliases, []string{"name.utf-8", "n"})
+	}
+
+	if !StructTagFromField(rt.Field(7)).IsInline || !StructTagFromField(rt.Field(8)).IsInline {
+		t.Fatal("inline option was not detected")
+	}
+	if zeroTag := StructTagFromField(rt.Field(10)); !zeroTag.IsOmitZero || zeroTag.IsOmitEmpty {
+		t.Fatal("omitzero option was not detected")
+	}
+	if customTag.IsOmitZero {
+		t.Fatal("omitzero option was detected without being set")
+	}
+	if !StructTagFromField(rt.Field(9)).IsString {
+		t.Fatal("string option was not detected")
+	}
+	if !StructTagFromField(rt.Field(11)).IsPacked || customTag.IsPacked {
+		t.Fatal("packed option was not detected")
+	}
+	if customTag.IsInline {
+		t.Fatal("inline option was detected without being set")
+	}
+
 	invalidTag := StructTagFromField(rt.Field(3))
 	if got := invalidTag.Name(); got != "Invalid" {
 		t.Fatalf("invalid tag should fall back to field name; got %q", got)
@@ -69,3 +110,72 @@ func TestValidTag(t *testing.T) {
 		})
 	}
 }
+
+type structOptionsFixture struct {
+	_     struct{} `bencode:",disallowunknown"`
+	Value int
+}
+
+func TestStructOptionsFromType(t *testing.T) {
+	if !StructOptionsFromType(reflect.TypeFor[structOptionsFixture]()).DisallowUnknownFields {
+		t.Fatal("disallowunknown option was not detected")
+	}
+
+	if StructOptionsFromType(reflect.TypeFor[tagFixture]()).DisallowUnknownFields {
+		t.Fatal("struct without blank field must not have options")
+	}
+}
+
+func TestParseDefault(t *testing.T) {
+	type Named string
+
+	cases := []struct {
+		rt   reflect.Type
+		raw  string
+		want any
+	}{
+		{rt: reflect.TypeFor[int](), raw: "-5", want: -5},
+		{rt: reflect.TypeFor[uint16](), raw: "1800", want: uint16(1800)},
+		{rt: reflect.TypeFor[bool](), raw: "true", want: true},
+		{rt: reflect.TypeFor[Named](), raw: "abc", want: Named("abc")},
+		{rt: reflect.TypeFor[[]byte](), raw: "abc", want: []byte("abc")},
+	}
+
+	for _, c := range cases {
+		v, err := ParseDefault(c.rt, c.raw)
+		if err != nil {
+			t.Fatalf("ParseDefault(%s, %q) returned error %v", c.rt, c.raw, err)
+		}
+		if !reflect.DeepEqual(v.Interface(), c.want) {
+			t.Fatalf("ParseDefault(%s, %q) = %v, want %v", c.rt, c.raw, v, c.want)
+		}
+	}
+
+	for _, c := range []struct {
+		rt  reflect.Type
+		raw string
+	}{
+		{rt: reflect.TypeFor[int8](), raw: "300"},
+		{rt: reflect.TypeFor[uint](), raw: "-1"},
+		{rt: reflect.TypeFor[bool](), raw: "yes"},
+		{rt: reflect.TypeFor[[]int](), raw: "1"},
+		{rt: reflect.TypeFor[*int](), raw: "1"},
+	} {
+		if _, err := ParseDefault(c.rt, c.raw); err == nil {
+			t.Fatalf("ParseDefault(%s, %q) should fail", c.rt, c.raw)
+		}
+	}
+}
+
+type tupleFixture struct {
+	_    struct{} `bencode:",tuple"`
+	Host string
+	Port int
+}
+
+func TestStructOptionsFromType_tuple(t *testing.T) {
+	o := StructOptionsFromType(reflect.TypeFor[tupleFixture]())
+	if !o.Tuple || o.DisallowUnknownFields {
+		t.Fatalf("unexpected options %+v", o)
+	}
+}
@@ -0,0 +1,19 @@
+package bencode
+
+import (
+	"github.com/trim21/go-bencode/internal/decoder"
+)
+
+// Limits bounds the resources used to decode untrusted input.
+//
+//	MaxDepth        max nesting of lists and dictionaries, default to 10000
+//	MaxStringLength max length of a decoded string
+//	MaxListLength   max number of elements in a decoded list
+//	MaxDictEntries  max number of entries in a decoded dictionary, including structs
+//	MaxAllocBytes   approximate memory budget for decoded strings, slices, maps and any values
+//
+// Zero value of a field means no limit. Values skipped by the decoder,
+// like dictionary keys without matching struct field, are only bounded by MaxDepth.
+// [Decoder] also checks MaxStringLength against every string of the input,
+// including dictionary keys and skipped values, before reading it.
+type Limits = decoder.Limits
@@ -0,0 +1,125 @@
+package bencode_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trim21/go-bencode"
+)
+
+func decodeWithLimits(raw string, limits bencode.Limits, v any) error {
+	dec := bencode.NewDecoder(strings.NewReader(raw))
+	dec.SetLimits(limits)
+	return dec.Decode(v)
+}
+
+func TestDecoder_SetLimits(t *testing.T) {
+	type Item struct {
+		Name string   `bencode:"name"`
+		List []string `bencode:"list"`
+	}
+
+	tests := []struct {
+		name   string
+		raw    string
+		limits bencode.Limits
+		v      any
+		limit  string
+		path   string
+	}{
+		{name: "depth any", raw: "llleee", limits: bencode.Limits{MaxDepth: 2}, v: new(any), limit: "MaxDepth"},
+		{name: "depth skipped field", raw: "d1:xllleeee", limits: bencode.Limits{MaxDepth: 3}, v: new(Item), limit: "MaxDepth"},
+		{name: "string", raw: "d4:name4:abcde", limits: bencode.Limits{MaxStringLength: 3}, v: new(Item), limit: "MaxStringLength"},
+		{name: "string any", raw: "l4:abcde", limits: bencode.Limits{MaxStringLength: 3}, v: new(any), limit: "MaxStringLength"},
+		{name: "bytes", raw: "4:abcd", limits: bencode.Limits{MaxStringLength: 3}, v: new([]byte), limit: "MaxStringLength"},
+		{name: "dict key any", raw: "d4:abcdi1ee", limits: bencode.Limits{MaxStringLength: 3}, v: new(any), limit: "MaxStringLength"},
+		{name: "list", raw: "d4:listl1:a1:b1:cee", limits: bencode.Limits{MaxListLength: 2}, v: new(Item), limit: "MaxListLength", path: "list"},
+		{name: "list any", raw: "li1ei2ei3ee", limits: bencode.Limits{MaxListLength: 2}, v: new(any), limit: "MaxListLength"},
+		{name: "map", raw: "d1:ai1e1:bi2ee", limits: bencode.Limits{MaxDictEntries: 1}, v: new(map[string]int), limit: "MaxDictEntries"},
+		{name: "struct", raw: "d4:listle4:name0:e", limits: bencode.Limits{MaxDictEntries: 1}, v: new(Item), limit: "MaxDictEntries"},
+		{name: "dict any", raw: "d1:ai1e1:bi2ee", limits: bencode.Limits{MaxDictEntries: 1}, v: new(any), limit: "MaxDictEntries"},
+		{name: "alloc", raw: "l" + strings.Repeat("0:", 1000) + "e", limits: bencode.Limits{MaxAllocBytes: 1000}, v: new(any), limit: "MaxAllocBytes"},
+		{name: "alloc slice", raw: "l" + strings.Repeat("0:", 1000) + "e", limits: bencode.Limits{MaxAllocBytes: 1000}, v: new([]string), limit: "MaxAllocBytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := decodeWithLimits(tt.raw, tt.limits, tt.v)
+
+			var limitError *bencode.LimitError
+			require.ErrorAs(t, err, &limitError)
+			require.Equal(t, tt.limit, limitError.Limit)
+			require.Equal(t, tt.path, limitError.Path.String())
+		})
+	}
+}
+
+func TestDecoder_SetLimits_string_length_prefix(t *testing.T) {
+	r := &countingReader{r: strings.NewReader("d4:name99999999999:" + strings.Repeat("a", 1<<20))}
+	dec := bencode.NewDecoder(r)
+	dec.SetLimits(bencode.Limits{MaxStringLength: 1024})
+
+	var v any
+	var limitError *bencode.LimitError
+	require.ErrorAs(t, dec.Decode(&v), &limitError)
+	require.Equal(t, "MaxStringLength", limitError.Limit)
+	require.Equal(t, 7, limitError.Offset)
+	require.Less(t, r.n, 1<<16)
+}
+
+func TestUnmarshalOptions_limits_path(t *testing.T) {
+	type Item struct {
+		Name string `bencode:"name"`
+	}
+
+	opts := bencode.UnmarshalOptions{Limits: bencode.Limits{MaxStringLength: 3}}
+
+	var limitError *bencode.LimitError
+	require.ErrorAs(t, opts.Unmarshal([]byte("d4:name4:abcde"), new(Item)), &limitError)
+	require.Equal(t, "name", limitError.Path.String())
+
+	require.ErrorAs(t, opts.Unmarshal([]byte("l4:abcde"), new(any)), &limitError)
+	require.Equal(t, "[0]", limitError.Path.String())
+}
+
+func TestDecoder_SetLimits_within_limits(t *testing.T) {
+	type Item struct {
+		Name string   `bencode:"name"`
+		List []string `bencode:"list"`
+	}
+
+	limits := bencode.Limits{
+		MaxDepth:        2,
+		MaxStringLength: 4,
+		MaxListLength:   2,
+		MaxDictEntries:  2,
+		MaxAllocBytes:   1024,
+	}
+
+	var v Item
+	require.NoError(t, decodeWithLimits("d4:listl1:a1:be4:name3:abce", limits, &v))
+	require.Equal(t, Item{Name: "abc", List: []string{"a", "b"}}, v)
+}
+
+func TestDecoder_SetLimits_many_empty_strings(t *testing.T) {
+	raw := []byte("l" + strings.Repeat("0:", 1_000_000) + "e")
+
+	dec := bencode.NewDecoder(bytes.NewReader(raw))
+	dec.SetLimits(bencode.Limits{MaxAllocBytes: 1 << 20})
+
+	var v any
+	var limitError *bencode.LimitError
+	require.ErrorAs(t, dec.Decode(&v), &limitError)
+	require.Nil(t, v)
+}
+
+func TestUnmarshal_default_max_depth_is_limit_error(t *testing.T) {
+	var v any
+	var limitError *bencode.LimitError
+	require.ErrorAs(t, bencode.Unmarshal(nestedLists(10001), &v), &limitError)
+	require.Equal(t, "MaxDepth", limitError.Limit)
+	require.EqualValues(t, 10000, limitError.Max)
+}
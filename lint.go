@@ -0,0 +1,40 @@
+package bencode
+
+import (
+	"github.com/trim21/go-bencode/internal/decoder"
+	"github.com/trim21/go-bencode/internal/errors"
+)
+
+// PathElement is a dictionary key or a list index in a [Path].
+type PathElement = errors.PathElement
+
+// Path is the location of a value in a bencode document, like `info.files[3].path[0]`.
+type Path = errors.Path
+
+// IssueKind is the type of non-canonical construct reported by [Lint].
+type IssueKind = decoder.IssueKind
+
+const (
+	IssueSyntax            = decoder.IssueSyntax            // input is malformed, linting stopped here
+	IssueUnsortedKey       = decoder.IssueUnsortedKey       // dictionary key is smaller than the previous key
+	IssueDuplicateKey      = decoder.IssueDuplicateKey      // dictionary key appeared before in the same dictionary
+	IssueIntLeadingZero    = decoder.IssueIntLeadingZero    // `i03e`
+	IssueNegativeZero      = decoder.IssueNegativeZero      // `i-0e`
+	IssueLengthLeadingZero = decoder.IssueLengthLeadingZero // `05:hello`
+	IssueTrailingData      = decoder.IssueTrailingData      // data after the top-level value
+)
+
+// Issue is a non-canonical construct found by [Lint].
+//
+// Offset is the index of the construct in input, Path is the location of the affected value.
+// For dictionary key issues, Path ends with the key itself.
+// Err is the syntax error of an [IssueSyntax] issue, and nil for other kinds.
+type Issue = decoder.Issue
+
+// Lint reports every non-canonical construct in data, instead of stopping at the first one like [Unmarshal].
+//
+// Malformed input can't be linted further, the syntax error is reported as the last issue with kind [IssueSyntax].
+// Lint returns nil if data is canonical bencode.
+func Lint(data []byte) []Issue {
+	return decoder.Lint(data)
+}
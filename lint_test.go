@@ -0,0 +1,91 @@
+package bencode_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trim21/go-bencode"
+)
+
+func TestLint_canonical(t *testing.T) {
+	require.Nil(t, bencode.Lint([]byte("d1:ali0ei-1ei10ee1:b0:1:cdee")))
+}
+
+func TestLint(t *testing.T) {
+	raw := "d4:infod5:filesld6:lengthi03e4:pathl05:helloeed6:lengthi-0eeee1:ai1e1:ai2ee0:i1e"
+
+	issues := bencode.Lint([]byte(raw))
+
+	type issue struct {
+		kind   bencode.IssueKind
+		offset int
+		path   string
+	}
+
+	var actual []issue
+	for _, i := range issues {
+		actual = append(actual, issue{kind: i.Kind, offset: i.Offset, path: i.Path.String()})
+	}
+
+	require.Equal(t, []issue{
+		{kind: bencode.IssueIntLeadingZero, offset: 25, path: "info.files[0].length"},
+		{kind: bencode.IssueLengthLeadingZero, offset: 36, path: "info.files[0].path[0]"},
+		{kind: bencode.IssueNegativeZero, offset: 55, path: "info.files[1].length"},
+		{kind: bencode.IssueUnsortedKey, offset: 62, path: "a"},
+		{kind: bencode.IssueDuplicateKey, offset: 68, path: "a"},
+		{kind: bencode.IssueTrailingData, offset: 75, path: ""},
+	}, actual)
+}
+
+func TestLint_duplicate_in_unsorted_dict(t *testing.T) {
+	issues := bencode.Lint([]byte("d1:ci1e1:ai1e1:ci1ee"))
+
+	require.Len(t, issues, 2)
+	require.Equal(t, bencode.IssueUnsortedKey, issues[0].Kind)
+	require.Equal(t, bencode.IssueDuplicateKey, issues[1].Kind)
+	require.Equal(t, 13, issues[1].Offset)
+}
+
+func TestLint_syntax_error(t *testing.T) {
+	for _, raw := range []string{"", "d1:bi1e1:ai", "x", "i1", "ie", "i-e", "10:a", "d1:a", "di1ei1ee", "lx", "l"} {
+		t.Run(raw, func(t *testing.T) {
+			issues := bencode.Lint([]byte(raw))
+			require.NotEmpty(t, issues)
+
+			last := issues[len(issues)-1]
+			require.Equal(t, bencode.IssueSyntax, last.Kind)
+			require.Error(t, last.Err)
+			require.NotEmpty(t, last.String())
+		})
+	}
+
+	issues := bencode.Lint([]byte("d1:bi1e1:ai"))
+	require.Equal(t, bencode.IssueUnsortedKey, issues[0].Kind)
+	require.Equal(t, "a", issues[1].Path.String())
+}
+
+func TestIssue_String(t *testing.T) {
+	issues := bencode.Lint([]byte("li01ee"))
+	require.Len(t, issues, 1)
+	require.Equal(t, "integer with leading zero at [0] (offset 1)", issues[0].String())
+
+	issues = bencode.Lint([]byte("i1ee"))
+	require.Equal(t, "trailing data (offset 3)", issues[0].String())
+}
+
+func TestPath_String(t *testing.T) {
+	path := bencode.Path{
+		{Key: "info"},
+		{Key: "files"},
+		{Index: 3, IsIndex: true},
+		{Key: "path.utf-8"},
+		{Index: 0, IsIndex: true},
+		{Key: ""},
+		{Key: "\x00"},
+	}
+
+	require.Equal(t, `info.files[3]["path.utf-8"][0][""]["\x00"]`, path.String())
+	require.Equal(t, "", bencode.Path(nil).String())
+	require.Equal(t, "[1].a", bencode.Path{{Index: 1, IsIndex: true}, {Key: "a"}}.String())
+}
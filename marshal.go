@@ -6,6 +6,12 @@ import (
 	"github.com/trim21/go-bencode/internal/encoder"
 )
 
+var (
+	ErrNilPtr   = encoder.ErrNilPtr   // a nil pointer in slice, map or interface
+	ErrNilValue = encoder.ErrNilValue // a nil interface value
+	ErrCycle    = encoder.ErrCycle    // a pointer, map or slice that refers to itself
+)
+
 // Marshaler allow users to implement its own encoder.
 type Marshaler interface {
 	MarshalBencode() ([]byte, error)
@@ -24,10 +30,20 @@ type IsZeroValue interface {
 	IsZeroBencodeValue() bool
 }
 
-func Marshal(v any) ([]byte, error) {
+// MarshalOptions configures encoding, its zero value encodes like [Marshal].
+type MarshalOptions struct {
+	// OmitDefaults skips struct fields equal to the value of their `default=...` tag option,
+	// decoding the output sets them back to the same value.
+	OmitDefaults bool
+}
+
+// Marshal returns the encoding of v with options o.
+func (o MarshalOptions) Marshal(v any) ([]byte, error) {
 	ctx := encoder.NewCtx()
 	defer encoder.FreeCtx(ctx)
 
+	ctx.Options = o.encoderOptions()
+
 	err := encoder.MarshalCtx(ctx, v)
 	if err != nil {
 		return nil, err
@@ -36,18 +52,70 @@ func Marshal(v any) ([]byte, error) {
 	return append([]byte(nil), ctx.Buf...), nil
 }
 
+// MarshalAppend is like [MarshalAppend] with options o.
+func (o MarshalOptions) MarshalAppend(dst []byte, v any) ([]byte, error) {
+	ctx := encoder.NewCtx()
+	defer encoder.FreeCtx(ctx)
+
+	ctx.Options = o.encoderOptions()
+
+	b, err := encoder.AppendValue(ctx, dst, v)
+	if err != nil {
+		return dst, err
+	}
+
+	return b, nil
+}
+
+func (o MarshalOptions) encoderOptions() encoder.Options {
+	return encoder.Options{OmitDefaults: o.OmitDefaults}
+}
+
+func Marshal(v any) ([]byte, error) {
+	return MarshalOptions{}.Marshal(v)
+}
+
+// MarshalAppend appends the encoding of v to dst and returns the extended buffer.
+// On error, dst is returned with its original length.
+func MarshalAppend(dst []byte, v any) ([]byte, error) {
+	return MarshalOptions{}.MarshalAppend(dst, v)
+}
+
 type Encoder struct {
-	w io.Writer
+	w              io.Writer
+	flushThreshold int
+	opts           MarshalOptions
 }
 
 func NewEncoder(w io.Writer) *Encoder {
 	return &Encoder{w: w}
 }
 
+// SetFlushThreshold makes Encoder write to the underlying io.Writer
+// every time about n bytes of output is buffered, instead of building the whole value in memory.
+// n <= 0 disables progressive flushing, which is the default.
+//
+// With progressive flushing, a failed Encode may have already written part of the value.
+func (e *Encoder) SetFlushThreshold(n int) {
+	e.flushThreshold = n
+}
+
+// SetOptions sets the options used by following calls to [Encoder.Encode].
+func (e *Encoder) SetOptions(o MarshalOptions) {
+	e.opts = o
+}
+
 func (e *Encoder) Encode(v any) error {
 	ctx := encoder.NewCtx()
 	defer encoder.FreeCtx(ctx)
 
+	ctx.Options = e.opts.encoderOptions()
+
+	if e.flushThreshold > 0 {
+		ctx.Writer = e.w
+		ctx.FlushThreshold = e.flushThreshold
+	}
+
 	err := encoder.MarshalCtx(ctx, v)
 	if err != nil {
 		return err
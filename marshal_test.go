@@ -1,10 +1,14 @@
 package bencode_test
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"math"
 	"math/big"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -970,6 +974,80 @@ func TestRecursive(t *testing.T) {
 	})
 }
 
+func TestMarshal_error_path(t *testing.T) {
+	t.Run("nil ptr in any", func(t *testing.T) {
+		type File struct {
+			Path []any `bencode:"path"`
+		}
+
+		v := map[string][]File{
+			"files": {{Path: []any{"a"}}, {Path: []any{"b", (*int)(nil)}}},
+		}
+
+		_, err := bencode.Marshal(v)
+		require.ErrorIs(t, err, bencode.ErrNilValue)
+
+		var marshalError *bencode.MarshalError
+		require.ErrorAs(t, err, &marshalError)
+		require.Equal(t, "files[1].path[1]", marshalError.Path.String())
+		require.Equal(t, reflect.TypeFor[*int](), marshalError.Type)
+		require.Contains(t, err.Error(), "files[1].path[1]")
+	})
+
+	t.Run("nil ptr", func(t *testing.T) {
+		one := 1
+		_, err := bencode.Marshal(map[string][]*int{"a": {&one, nil}})
+		require.ErrorIs(t, err, bencode.ErrNilPtr)
+
+		var marshalError *bencode.MarshalError
+		require.ErrorAs(t, err, &marshalError)
+		require.Equal(t, "a[1]", marshalError.Path.String())
+	})
+
+	t.Run("nil value", func(t *testing.T) {
+		_, err := bencode.Marshal([2]any{1})
+		require.ErrorIs(t, err, bencode.ErrNilValue)
+
+		var marshalError *bencode.MarshalError
+		require.ErrorAs(t, err, &marshalError)
+		require.Equal(t, "[1]", marshalError.Path.String())
+	})
+
+	t.Run("marshaler", func(t *testing.T) {
+		var v struct {
+			Value errMarshaler `bencode:"value"`
+		}
+
+		_, err := bencode.Marshal(v)
+		require.ErrorIs(t, err, errMarshal)
+
+		var marshalError *bencode.MarshalError
+		require.ErrorAs(t, err, &marshalError)
+		require.Equal(t, "value", marshalError.Path.String())
+		require.Equal(t, reflect.TypeFor[errMarshaler](), marshalError.Type)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		m := map[string]any{}
+		m["self"] = m
+
+		_, err := bencode.Marshal(m)
+		require.ErrorIs(t, err, bencode.ErrCycle)
+
+		var marshalError *bencode.MarshalError
+		require.ErrorAs(t, err, &marshalError)
+		require.Equal(t, bencode.PathElement{Key: "self"}, marshalError.Path[0])
+	})
+}
+
+var errMarshal = errors.New("marshal error")
+
+type errMarshaler struct{}
+
+func (errMarshaler) MarshalBencode() ([]byte, error) {
+	return nil, errMarshal
+}
+
 type userMarshaler struct {
 	t time.Time
 }
@@ -1232,6 +1310,60 @@ func TestNewEncoder_Encode(t *testing.T) {
 	})
 }
 
+type chunkWriter struct {
+	chunks [][]byte
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	w.chunks = append(w.chunks, append([]byte(nil), p...))
+	return len(p), nil
+}
+
+func TestEncoder_SetFlushThreshold(t *testing.T) {
+	value := map[string][]string{
+		"a": slices.Repeat([]string{strings.Repeat("x", 100)}, 100),
+		"b": slices.Repeat([]string{strings.Repeat("y", 100)}, 100),
+	}
+
+	expected, err := bencode.Marshal(value)
+	require.NoError(t, err)
+
+	var w chunkWriter
+	enc := bencode.NewEncoder(&w)
+	enc.SetFlushThreshold(1024)
+	require.NoError(t, enc.Encode(value))
+
+	require.Greater(t, len(w.chunks), 1)
+	for _, chunk := range w.chunks {
+		require.LessOrEqual(t, len(chunk), 1024+200)
+	}
+	test.StringEqual(t, expected, bytes.Join(w.chunks, nil))
+
+	t.Run("write error", func(t *testing.T) {
+		enc := bencode.NewEncoder(errWriter{})
+		enc.SetFlushThreshold(10)
+		require.Error(t, enc.Encode(value))
+	})
+}
+
+func TestMarshalAppend(t *testing.T) {
+	dst := make([]byte, 0, 64)
+	dst = append(dst, "prefix"...)
+
+	b, err := bencode.MarshalAppend(dst, Item{V: 7})
+	require.NoError(t, err)
+	require.Equal(t, "prefixd1:vi7ee", string(b))
+	require.Equal(t, &dst[:1][0], &b[:1][0], "should append in place when capacity is enough")
+
+	b, err = bencode.MarshalAppend(dst, []any{1, nil})
+	require.Error(t, err)
+	require.Equal(t, "prefix", string(b))
+
+	b, err = bencode.MarshalAppend(nil, "a")
+	require.NoError(t, err)
+	require.Equal(t, "1:a", string(b))
+}
+
 func TestMarshal_byte_slice_standalone(t *testing.T) {
 	actual, err := bencode.Marshal([]byte("hello"))
 	require.NoError(t, err)
@@ -0,0 +1,334 @@
+package bencode_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trim21/go-bencode"
+)
+
+func TestUnmarshalOptions_zero_value(t *testing.T) {
+	var v map[string]int
+	require.NoError(t, bencode.UnmarshalOptions{}.Unmarshal([]byte("d1:ai1e1:bi2ee"), &v))
+	require.Equal(t, map[string]int{"a": 1, "b": 2}, v)
+
+	var syntaxError *bencode.SyntaxError
+	require.ErrorAs(t, bencode.UnmarshalOptions{}.Unmarshal([]byte("d1:bi2e1:ai1ee"), &v), &syntaxError)
+
+	require.ErrorContains(t, bencode.UnmarshalOptions{}.Unmarshal(nil, &v), "empty data")
+}
+
+func TestUnmarshalOptions_relaxed(t *testing.T) {
+	var v map[string]int
+	require.NoError(t, bencode.UnmarshalOptions{Relaxed: true}.Unmarshal([]byte("d1:bi2e1:ai1ee"), &v))
+	require.Equal(t, map[string]int{"a": 1, "b": 2}, v)
+}
+
+func TestUnmarshalOptions_collect_errors(t *testing.T) {
+	var v struct {
+		A int8 `bencode:"a"`
+		B int8 `bencode:"b"`
+		C int8 `bencode:"c"`
+	}
+
+	err := bencode.UnmarshalOptions{CollectErrors: true}.Unmarshal([]byte("d1:ai300e1:bi2e1:c1:xe"), &v)
+
+	var errs *bencode.UnmarshalErrors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs.Errors, 2)
+	require.EqualValues(t, 2, v.B)
+}
+
+func TestUnmarshalOptions_collect_errors_bool(t *testing.T) {
+	var v struct {
+		A bool   `bencode:"a"`
+		B string `bencode:"b"`
+	}
+
+	err := bencode.UnmarshalOptions{CollectErrors: true}.Unmarshal([]byte("d1:ai2e1:b1:xe"), &v)
+
+	var errs *bencode.UnmarshalErrors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs.Errors, 1)
+	require.Equal(t, "x", v.B)
+
+	var typeError *bencode.UnmarshalTypeError
+	require.ErrorAs(t, errs.Errors[0], &typeError)
+	require.Equal(t, "number 2", typeError.Value)
+	require.Equal(t, 4, typeError.Offset)
+}
+
+func TestUnmarshalOptions_limits(t *testing.T) {
+	var v any
+	err := bencode.UnmarshalOptions{Limits: bencode.Limits{MaxListLength: 1}}.Unmarshal([]byte("li1ei2ee"), &v)
+
+	var limitError *bencode.LimitError
+	require.ErrorAs(t, err, &limitError)
+	require.Equal(t, "MaxListLength", limitError.Limit)
+}
+
+func TestUnmarshalOptions_UnmarshalPrefix(t *testing.T) {
+	var v map[string]int
+	rest, err := bencode.UnmarshalOptions{Relaxed: true}.UnmarshalPrefix([]byte("d1:bi2e1:ai1eepayload"), &v)
+	require.NoError(t, err)
+	require.Equal(t, "payload", string(rest))
+	require.Equal(t, map[string]int{"a": 1, "b": 2}, v)
+}
+
+func TestDecoder_SetRelaxed(t *testing.T) {
+	dec := bencode.NewDecoder(strings.NewReader("d1:bi2e1:ai1ee"))
+	dec.SetRelaxed(true)
+
+	var v map[string]int
+	require.NoError(t, dec.Decode(&v))
+	require.Equal(t, map[string]int{"a": 1, "b": 2}, v)
+}
+
+func TestDecoder_SetOptions(t *testing.T) {
+	dec := bencode.NewDecoder(strings.NewReader("d1:bi2e1:ai1eellleee"))
+	dec.SetOptions(bencode.UnmarshalOptions{Relaxed: true, Limits: bencode.Limits{MaxDepth: 2}})
+
+	var v any
+	require.NoError(t, dec.Decode(&v))
+
+	var limitError *bencode.LimitError
+	require.ErrorAs(t, dec.Decode(&v), &limitError)
+	require.Equal(t, "MaxDepth", limitError.Limit)
+}
+
+func TestDecoder_SetOptions_replaces_options(t *testing.T) {
+	dec := bencode.NewDecoder(strings.NewReader("lleed1:bi2e1:ai1ee"))
+	dec.SetLimits(bencode.Limits{MaxDepth: 1})
+	dec.SetRelaxed(true)
+	dec.SetOptions(bencode.UnmarshalOptions{})
+
+	var v any
+	require.NoError(t, dec.Decode(&v))
+
+	var syntaxError *bencode.SyntaxError
+	require.ErrorAs(t, dec.Decode(&v), &syntaxError)
+}
+
+func TestMarshalOptions(t *testing.T) {
+	v := map[string]any{"b": 2, "a": "1"}
+
+	expected, err := bencode.Marshal(v)
+	require.NoError(t, err)
+
+	actual, err := bencode.MarshalOptions{}.Marshal(v)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+
+	actual, err = bencode.MarshalOptions{}.MarshalAppend([]byte("prefix"), v)
+	require.NoError(t, err)
+	require.Equal(t, append([]byte("prefix"), expected...), actual)
+
+	var buf bytes.Buffer
+	enc := bencode.NewEncoder(&buf)
+	enc.SetOptions(bencode.MarshalOptions{})
+	require.NoError(t, enc.Encode(v))
+	require.Equal(t, expected, buf.Bytes())
+}
+
+func TestUnmarshalOptions_lenient_numbers(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected int64
+	}{
+		{raw: "i03e", expected: 3},
+		{raw: "i000e", expected: 0},
+		{raw: "i-0e", expected: 0},
+		{raw: "i-00e", expected: 0},
+		{raw: "i+5e", expected: 5},
+		{raw: "i-05e", expected: -5},
+		{raw: "i 5 e", expected: 5},
+		{raw: "i\t-12\r\ne", expected: -12},
+	}
+
+	opts := bencode.UnmarshalOptions{LenientNumbers: true}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			var i int64
+			require.NoError(t, opts.Unmarshal([]byte(tt.raw), &i))
+			require.Equal(t, tt.expected, i)
+
+			var u any
+			require.NoError(t, opts.Unmarshal([]byte(tt.raw), &u))
+			require.Equal(t, tt.expected, u)
+
+			require.Error(t, bencode.Unmarshal([]byte(tt.raw), &i))
+		})
+	}
+}
+
+func TestUnmarshalOptions_lenient_numbers_invalid(t *testing.T) {
+	opts := bencode.UnmarshalOptions{LenientNumbers: true}
+
+	for _, raw := range []string{"ie", "i e", "i-e", "i+-1e", "i1 2e", "i0x1e", "i1.5e"} {
+		t.Run(raw, func(t *testing.T) {
+			var i int64
+			var syntaxError *bencode.SyntaxError
+			require.ErrorAs(t, opts.Unmarshal([]byte(raw), &i), &syntaxError)
+		})
+	}
+}
+
+func TestUnmarshalOptions_lenient_numbers_bool(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected bool
+	}{
+		{raw: "i01e", expected: true},
+		{raw: "i+1e", expected: true},
+		{raw: "i 1 e", expected: true},
+		{raw: "i-0e", expected: false},
+		{raw: "i00e", expected: false},
+	}
+
+	opts := bencode.UnmarshalOptions{LenientNumbers: true}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			var b bool
+			require.NoError(t, opts.Unmarshal([]byte(tt.raw), &b))
+			require.Equal(t, tt.expected, b)
+
+			require.Error(t, bencode.Unmarshal([]byte(tt.raw), &b))
+		})
+	}
+
+	for _, raw := range []string{"i2e", "i-1e", "i 10 e"} {
+		t.Run(raw, func(t *testing.T) {
+			var b bool
+			var typeError *bencode.UnmarshalTypeError
+			require.ErrorAs(t, opts.Unmarshal([]byte(raw), &b), &typeError)
+		})
+	}
+
+	var b bool
+	var syntaxError *bencode.SyntaxError
+	require.ErrorAs(t, opts.Unmarshal([]byte("i e"), &b), &syntaxError)
+}
+
+func TestUnmarshalOptions_lenient_numbers_struct(t *testing.T) {
+	var v struct {
+		Name   string `bencode:"name"`
+		Length uint64 `bencode:"length"`
+		Other  int    `bencode:"other"`
+	}
+
+	raw := []byte("d06:lengthi+0010e04:name03:abc5:otheri-0e4:skipli01e2:0xee")
+
+	require.Error(t, bencode.Unmarshal(raw, &v))
+	require.NoError(t, bencode.UnmarshalOptions{LenientNumbers: true}.Unmarshal(raw, &v))
+	require.Equal(t, "abc", v.Name)
+	require.EqualValues(t, 10, v.Length)
+	require.Equal(t, 0, v.Other)
+
+	var u any
+	require.NoError(t, bencode.UnmarshalOptions{LenientNumbers: true}.Unmarshal(raw, &u))
+}
+
+func TestDecoder_SetOptions_lenient_numbers(t *testing.T) {
+	dec := bencode.NewDecoder(iotest.OneByteReader(strings.NewReader("i 03 e03:abcli+1ee")))
+	dec.SetOptions(bencode.UnmarshalOptions{LenientNumbers: true})
+
+	var i int
+	require.NoError(t, dec.Decode(&i))
+	require.Equal(t, 3, i)
+
+	var s string
+	require.NoError(t, dec.Decode(&s))
+	require.Equal(t, "abc", s)
+
+	var l []int
+	require.NoError(t, dec.Decode(&l))
+	require.Equal(t, []int{1}, l)
+}
+
+func TestDecoder_SetOptions_lenient_numbers_invalid(t *testing.T) {
+	r := &countingReader{r: strings.NewReader("i 1x" + strings.Repeat("x", 1<<20))}
+	dec := bencode.NewDecoder(iotest.OneByteReader(r))
+	dec.SetOptions(bencode.UnmarshalOptions{LenientNumbers: true})
+
+	var i int
+	var syntaxError *bencode.SyntaxError
+	require.ErrorAs(t, dec.Decode(&i), &syntaxError)
+	require.Equal(t, 3, syntaxError.Offset)
+	require.Less(t, r.n, 16)
+}
+
+func TestUnmarshalOptions_disallow_unknown_fields(t *testing.T) {
+	type Item struct {
+		Name string `bencode:"name"`
+	}
+
+	var v struct {
+		Items []Item `bencode:"items"`
+	}
+
+	raw := []byte("d5:itemsld4:name1:a3:othi1eeee")
+
+	require.NoError(t, bencode.Unmarshal(raw, &v))
+
+	err := bencode.UnmarshalOptions{DisallowUnknownFields: true}.Unmarshal(raw, &v)
+
+	var unknownFieldError *bencode.UnknownFieldError
+	require.ErrorAs(t, err, &unknownFieldError)
+	require.Equal(t, "oth", unknownFieldError.Key)
+	require.Equal(t, reflect.TypeFor[Item](), unknownFieldError.Type)
+	require.Equal(t, 19, unknownFieldError.Offset)
+	require.Equal(t, "items[0].oth", unknownFieldError.Path.String())
+	require.Equal(t, `bencode: unknown field "oth" for Go struct bencode_test.Item at items[0].oth (offset 19)`, err.Error())
+}
+
+type strictMessage struct {
+	_    struct{} `bencode:",disallowunknown"`
+	Name string   `bencode:"name"`
+}
+
+func TestUnmarshal_struct_disallow_unknown_fields(t *testing.T) {
+	var v strictMessage
+	require.NoError(t, bencode.Unmarshal([]byte("d4:name1:ae"), &v))
+	require.Equal(t, "a", v.Name)
+
+	var unknownFieldError *bencode.UnknownFieldError
+	require.ErrorAs(t, bencode.Unmarshal([]byte("d4:name1:a7:versioni2ee"), &v), &unknownFieldError)
+	require.Equal(t, "version", unknownFieldError.Key)
+
+	// other structs still skip unknown keys.
+	var loose struct {
+		Message strictMessage `bencode:"msg"`
+		Other   int           `bencode:"other"`
+	}
+	require.NoError(t, bencode.Unmarshal([]byte("d3:msgd4:name1:ae3:unki1ee"), &loose))
+
+	encoded, err := bencode.Marshal(strictMessage{Name: "a"})
+	require.NoError(t, err)
+	require.Equal(t, "d4:name1:ae", string(encoded))
+}
+
+func TestUnmarshalOptions_disallow_unknown_fields_collect_errors(t *testing.T) {
+	var v struct {
+		A int8 `bencode:"a"`
+		C int8 `bencode:"c"`
+	}
+
+	opts := bencode.UnmarshalOptions{DisallowUnknownFields: true, CollectErrors: true}
+	err := opts.Unmarshal([]byte("d1:ai1e1:bli1ee1:ci300ee"), &v)
+
+	var errs *bencode.UnmarshalErrors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs.Errors, 2)
+
+	var unknownFieldError *bencode.UnknownFieldError
+	require.ErrorAs(t, errs.Errors[0], &unknownFieldError)
+	require.Equal(t, "b", unknownFieldError.Path.String())
+	require.EqualValues(t, 1, v.A)
+}
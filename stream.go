@@ -0,0 +1,180 @@
+package bencode
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/trim21/go-bencode/internal/decoder"
+)
+
+// A Decoder reads and decodes bencode values from an input stream.
+//
+// Values in the stream are expected to be concatenated without any separator,
+// Decoder only reads as much data as it needs to complete the next value.
+type Decoder struct {
+	r     io.Reader
+	buf   []byte
+	scanp int   // start of unread data in buf
+	err   error // sticky error, input stream can't be resynced
+
+	scanned int64 // amount of data already scanned and dropped from buf
+
+	scan decoder.Scanner
+
+	opts decoder.Options
+}
+
+// NewDecoder returns a new decoder that reads from r.
+//
+// The decoder introduces its own buffering and may
+// read data from r beyond the bencode values requested.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// Decode reads the next bencode value from its input and stores it in the value pointed to by v.
+//
+// It returns [io.EOF] if there is no more value in the input,
+// and [io.ErrUnexpectedEOF] if input ends in the middle of a value.
+func (dec *Decoder) Decode(v any) error {
+	if dec.err != nil {
+		return dec.err
+	}
+
+	n, err := dec.readValue()
+	if err != nil {
+		return err
+	}
+
+	err = decoder.UnmarshalWithOptions(dec.buf[dec.scanp:dec.scanp+n], v, dec.opts)
+
+	// value is consumed even if it can't be decoded into v.
+	dec.scanp += n
+	dec.scan.Reset()
+
+	return err
+}
+
+// SetOptions sets the options used by following calls to [Decoder.Decode].
+// It replaces all options, including those set by earlier calls to
+// [Decoder.SetRelaxed], [Decoder.SetCollectErrors] and [Decoder.SetLimits].
+func (dec *Decoder) SetOptions(o UnmarshalOptions) {
+	dec.opts = o.decoderOptions()
+	dec.scan.Limits = o.Limits
+	dec.scan.LenientNumbers = o.LenientNumbers
+}
+
+// SetRelaxed makes Decode use the relaxed parsing rules of [UnmarshalRelaxed].
+func (dec *Decoder) SetRelaxed(on bool) {
+	dec.opts.Relaxed = on
+}
+
+// SetCollectErrors makes Decode keep going after a value that doesn't fit its Go type.
+// The value is skipped and left as zero value, and Decode returns an [*UnmarshalErrors]
+// listing every type error with its offset and path. Syntax errors still stop decoding.
+func (dec *Decoder) SetCollectErrors(on bool) {
+	dec.opts.CollectErrors = on
+}
+
+// SetLimits bounds the resources used to decode each value, see [Limits].
+// Decode returns a [*LimitError] when a value exceeds them.
+// MaxDepth and MaxStringLength are checked while reading the input,
+// so a value exceeding them is rejected before it's fully buffered.
+func (dec *Decoder) SetLimits(l Limits) {
+	dec.opts.Limits = l
+	dec.scan.Limits = l
+}
+
+// Buffered returns a reader of the data remaining in the Decoder's buffer.
+// The reader is valid until the next call to [Decoder.Decode].
+func (dec *Decoder) Buffered() io.Reader {
+	return bytes.NewReader(dec.buf[dec.scanp:])
+}
+
+// InputOffset returns the input stream byte offset of the current decoder position.
+// The offset gives the location of the end of the most recently returned value
+// and the beginning of the next value.
+func (dec *Decoder) InputOffset() int64 {
+	return dec.scanned + int64(dec.scanp)
+}
+
+// More reports whether there is another value in the input stream.
+func (dec *Decoder) More() bool {
+	return dec.peek() == nil
+}
+
+// readValue reads a complete bencode value into buf.
+// It returns the length of the value.
+func (dec *Decoder) readValue() (int, error) {
+	var err error
+	for {
+		n, ok, scanErr := dec.scan.Scan(dec.buf[dec.scanp:])
+		if scanErr != nil {
+			dec.err = scanErr
+			return 0, scanErr
+		}
+
+		if ok {
+			return n, nil
+		}
+
+		// Did the last read have an error?
+		// Delayed until now to allow buffer scan.
+		if err != nil {
+			if err == io.EOF {
+				if len(dec.buf) > dec.scanp {
+					err = io.ErrUnexpectedEOF
+				}
+			}
+			dec.err = err
+			return 0, err
+		}
+
+		err = dec.refill()
+	}
+}
+
+func (dec *Decoder) refill() error {
+	// Make room to read more into the buffer.
+	// First slide down data already consumed.
+	if dec.scanp > 0 {
+		dec.scanned += int64(dec.scanp)
+		n := copy(dec.buf, dec.buf[dec.scanp:])
+		dec.buf = dec.buf[:n]
+		dec.scanp = 0
+	}
+
+	// Grow buffer if not large enough.
+	const minRead = 512
+	if cap(dec.buf)-len(dec.buf) < minRead {
+		newBuf := make([]byte, len(dec.buf), 2*cap(dec.buf)+minRead)
+		copy(newBuf, dec.buf)
+		dec.buf = newBuf
+	}
+
+	// Read. Delay error for next iteration (after scan).
+	n, err := dec.r.Read(dec.buf[len(dec.buf):cap(dec.buf)])
+	dec.buf = dec.buf[0 : len(dec.buf)+n]
+
+	return err
+}
+
+func (dec *Decoder) peek() error {
+	if dec.err != nil {
+		return dec.err
+	}
+
+	var err error
+	for {
+		if dec.scanp < len(dec.buf) {
+			return nil
+		}
+
+		// buffer has been scanned, now report any error
+		if err != nil {
+			return err
+		}
+
+		err = dec.refill()
+	}
+}
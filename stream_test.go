@@ -0,0 +1,217 @@
+package bencode_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trim21/go-bencode"
+)
+
+func TestDecoder_Decode(t *testing.T) {
+	type Item struct {
+		V int    `bencode:"v"`
+		S string `bencode:"s"`
+	}
+
+	raw := "d1:s1:a1:vi1eed1:s2:bb1:vi2eei3e"
+
+	dec := bencode.NewDecoder(strings.NewReader(raw))
+
+	var a, b Item
+	require.True(t, dec.More())
+	require.NoError(t, dec.Decode(&a))
+	require.Equal(t, Item{V: 1, S: "a"}, a)
+	require.EqualValues(t, 14, dec.InputOffset())
+
+	require.True(t, dec.More())
+	require.NoError(t, dec.Decode(&b))
+	require.Equal(t, Item{V: 2, S: "bb"}, b)
+
+	var i int
+	require.True(t, dec.More())
+	require.NoError(t, dec.Decode(&i))
+	require.Equal(t, 3, i)
+	require.EqualValues(t, len(raw), dec.InputOffset())
+
+	require.False(t, dec.More())
+	require.ErrorIs(t, dec.Decode(&i), io.EOF)
+}
+
+func TestDecoder_Decode_one_byte_reader(t *testing.T) {
+	raw := "d4:listli1ei2ee3:str10:0123456789e" + "li100ee"
+
+	dec := bencode.NewDecoder(iotest.OneByteReader(strings.NewReader(raw)))
+
+	var v map[string]any
+	require.NoError(t, dec.Decode(&v))
+	require.Equal(t, map[string]any{
+		"list": []any{int64(1), int64(2)},
+		"str":  "0123456789",
+	}, v)
+
+	var l []int
+	require.NoError(t, dec.Decode(&l))
+	require.Equal(t, []int{100}, l)
+
+	require.ErrorIs(t, dec.Decode(&l), io.EOF)
+}
+
+func TestDecoder_Decode_large_value(t *testing.T) {
+	s := strings.Repeat("x", 100000)
+	var buf bytes.Buffer
+	require.NoError(t, bencode.NewEncoder(&buf).Encode([]string{s, s}))
+
+	dec := bencode.NewDecoder(&buf)
+	var v []string
+	require.NoError(t, dec.Decode(&v))
+	require.Equal(t, []string{s, s}, v)
+}
+
+func TestDecoder_Decode_unexpected_eof(t *testing.T) {
+	for _, raw := range []string{"d1:a", "i12", "10:abc", "l"} {
+		t.Run(raw, func(t *testing.T) {
+			var v any
+			err := bencode.NewDecoder(strings.NewReader(raw)).Decode(&v)
+			require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		})
+	}
+}
+
+func TestDecoder_Decode_syntax_error(t *testing.T) {
+	for _, raw := range []string{"e", "x", "i1x", "1x:a", "li1ex"} {
+		t.Run(raw, func(t *testing.T) {
+			dec := bencode.NewDecoder(strings.NewReader(raw))
+			var v any
+			err := dec.Decode(&v)
+			require.Error(t, err)
+			require.NotErrorIs(t, err, io.ErrUnexpectedEOF)
+
+			// syntax error is sticky
+			require.Equal(t, err, dec.Decode(&v))
+			require.False(t, dec.More())
+		})
+	}
+}
+
+func TestDecoder_Decode_type_error(t *testing.T) {
+	dec := bencode.NewDecoder(strings.NewReader("1:ai1e"))
+
+	var i int
+	require.Error(t, dec.Decode(&i))
+
+	// mismatched value is consumed, decoder can continue.
+	require.NoError(t, dec.Decode(&i))
+	require.Equal(t, 1, i)
+}
+
+func TestDecoder_Buffered(t *testing.T) {
+	dec := bencode.NewDecoder(strings.NewReader("i1e raw tail"))
+
+	var i int
+	require.NoError(t, dec.Decode(&i))
+
+	rest, err := io.ReadAll(dec.Buffered())
+	require.NoError(t, err)
+	require.Equal(t, " raw tail", string(rest))
+}
+
+func TestDecoder_SetCollectErrors(t *testing.T) {
+	type File struct {
+		Length int    `bencode:"length"`
+		Path   []int8 `bencode:"path"`
+	}
+
+	type Info struct {
+		Name  string         `bencode:"name"`
+		Files []File         `bencode:"files"`
+		Hash  [2]byte        `bencode:"hash"`
+		Meta  map[string]int `bencode:"meta"`
+	}
+
+	raw := "d" +
+		"5:filesl" +
+		"d6:length1:x4:pathli1ei300eee" +
+		"d6:lengthi2e4:pathli3eee" +
+		"e" +
+		"4:hash3:abc" +
+		"4:metad1:ai1e1:b0:e" +
+		"4:namei1e" +
+		"e"
+
+	dec := bencode.NewDecoder(strings.NewReader(raw + "i1e"))
+	dec.SetCollectErrors(true)
+
+	var v Info
+	err := dec.Decode(&v)
+
+	var errs *bencode.UnmarshalErrors
+	require.ErrorAs(t, err, &errs)
+
+	var paths []string
+	for _, err := range errs.Errors {
+		var typeError *bencode.UnmarshalTypeError
+		require.ErrorAs(t, err, &typeError)
+		paths = append(paths, typeError.Path.String())
+	}
+
+	require.Equal(t, []string{"files[0].length", "files[0].path[1]", "hash", "meta.b", "name"}, paths)
+	require.Equal(t, Info{
+		Files: []File{{Path: []int8{1, 0}}, {Length: 2, Path: []int8{3}}},
+		Meta:  map[string]int{"a": 1, "b": 0},
+	}, v)
+
+	var typeError *bencode.UnmarshalTypeError
+	require.ErrorAs(t, err, &typeError, "errors.As finds the first error")
+	require.Equal(t, "files[0].length", typeError.Path.String())
+
+	var i int
+	require.NoError(t, dec.Decode(&i), "decoder continues with next value")
+	require.Equal(t, 1, i)
+}
+
+func TestDecoder_SetCollectErrors_syntax_error(t *testing.T) {
+	type Item struct {
+		A int `bencode:"a"`
+		B int `bencode:"b"`
+	}
+
+	dec := bencode.NewDecoder(strings.NewReader("d1:a1:x1:bi01ee"))
+	dec.SetCollectErrors(true)
+
+	var v Item
+	err := dec.Decode(&v)
+
+	var syntaxError *bencode.SyntaxError
+	require.ErrorAs(t, err, &syntaxError)
+	require.NotErrorAs(t, err, new(*bencode.UnmarshalErrors))
+}
+
+func TestDecoder_SetCollectErrors_map_key(t *testing.T) {
+	dec := bencode.NewDecoder(strings.NewReader("d2:aai1e3:bbbi2e2:cci3ee"))
+	dec.SetCollectErrors(true)
+
+	var v map[[2]byte]int
+	err := dec.Decode(&v)
+
+	var errs *bencode.UnmarshalErrors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs.Errors, 1)
+	require.Equal(t, map[[2]byte]int{{'a', 'a'}: 1, {'c', 'c'}: 3}, v)
+}
+
+// countingReader counts bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
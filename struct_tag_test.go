@@ -0,0 +1,709 @@
+package bencode_test
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trim21/go-bencode"
+)
+
+type torrentInfo struct {
+	Name        string `bencode:"name,required"`
+	PieceLength int64  `bencode:"piece length,required"`
+	Pieces      []byte `bencode:"pieces,required"`
+	Private     bool   `bencode:"private"`
+}
+
+func TestUnmarshal_required(t *testing.T) {
+	var v torrentInfo
+	require.NoError(t, bencode.Unmarshal([]byte("d4:name1:a12:piece lengthi0e6:pieces0:e"), &v))
+	require.Equal(t, torrentInfo{Name: "a", Pieces: []byte{}}, v)
+}
+
+func TestUnmarshal_required_missing(t *testing.T) {
+	var v struct {
+		Info torrentInfo `bencode:"info"`
+	}
+
+	err := bencode.Unmarshal([]byte("d4:infod4:name1:a7:privatei1eee"), &v)
+
+	var missingFieldsError *bencode.MissingFieldsError
+	require.ErrorAs(t, err, &missingFieldsError)
+	require.Equal(t, []string{"piece length", "pieces"}, missingFieldsError.Keys)
+	require.Equal(t, reflect.TypeFor[torrentInfo](), missingFieldsError.Type)
+	require.Equal(t, 7, missingFieldsError.Offset)
+	require.Equal(t, "info", missingFieldsError.Path.String())
+	require.Equal(t, `bencode: missing required fields "piece length", "pieces" for Go struct bencode_test.torrentInfo at info (offset 7)`, err.Error())
+}
+
+func TestUnmarshal_required_embedded(t *testing.T) {
+	type Base struct {
+		ID string `bencode:"id,required"`
+	}
+
+	var v struct {
+		Base
+		Name string `bencode:"name"`
+	}
+
+	var missingFieldsError *bencode.MissingFieldsError
+	require.ErrorAs(t, bencode.Unmarshal([]byte("d4:name1:ae"), &v), &missingFieldsError)
+	require.Equal(t, []string{"id"}, missingFieldsError.Keys)
+
+	require.NoError(t, bencode.Unmarshal([]byte("d2:id1:x4:name1:ae"), &v))
+	require.Equal(t, "x", v.ID)
+}
+
+func TestUnmarshal_required_many(t *testing.T) {
+	type Many struct {
+		F00 int `bencode:"f00,required"`
+		F01 int `bencode:"f01,required"`
+		F02 int `bencode:"f02,required"`
+		F03 int `bencode:"f03,required"`
+		F04 int `bencode:"f04,required"`
+		F05 int `bencode:"f05,required"`
+		F06 int `bencode:"f06,required"`
+		F07 int `bencode:"f07,required"`
+		F08 int `bencode:"f08,required"`
+		F09 int `bencode:"f09,required"`
+		F10 int `bencode:"f10,required"`
+		F11 int `bencode:"f11,required"`
+		F12 int `bencode:"f12,required"`
+		F13 int `bencode:"f13,required"`
+		F14 int `bencode:"f14,required"`
+		F15 int `bencode:"f15,required"`
+		F16 int `bencode:"f16,required"`
+	}
+
+	var v Many
+	var missingFieldsError *bencode.MissingFieldsError
+	require.ErrorAs(t, bencode.Unmarshal([]byte("d3:f00i1e3:f16i1ee"), &v), &missingFieldsError)
+	require.Len(t, missingFieldsError.Keys, 15)
+	require.Equal(t, "f01", missingFieldsError.Keys[0])
+	require.Equal(t, "f15", missingFieldsError.Keys[14])
+}
+
+func TestUnmarshal_required_collect_errors(t *testing.T) {
+	var v []torrentInfo
+
+	opts := bencode.UnmarshalOptions{CollectErrors: true}
+	err := opts.Unmarshal([]byte("ld4:name1:a12:piece lengthi1e6:pieces0:ed4:name1:bee"), &v)
+
+	var errs *bencode.UnmarshalErrors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs.Errors, 1)
+
+	var missingFieldsError *bencode.MissingFieldsError
+	require.ErrorAs(t, errs.Errors[0], &missingFieldsError)
+	require.Equal(t, "[1]", missingFieldsError.Path.String())
+	require.Len(t, v, 2)
+	require.Equal(t, "b", v[1].Name)
+}
+
+type announceResponse struct {
+	Interval    int    `bencode:"interval,default=1800"`
+	MinInterval uint32 `bencode:"min interval,default=60"`
+	Compact     bool   `bencode:"compact,default=true"`
+	TrackerID   string `bencode:"tracker id,default=none"`
+	Key         []byte `bencode:"key,default=abc"`
+	Complete    int    `bencode:"complete"`
+}
+
+func TestUnmarshal_default(t *testing.T) {
+	var v announceResponse
+	require.NoError(t, bencode.Unmarshal([]byte("d8:completei5e8:intervali0ee"), &v))
+	require.Equal(t, announceResponse{
+		Interval:    0,
+		MinInterval: 60,
+		Compact:     true,
+		TrackerID:   "none",
+		Key:         []byte("abc"),
+		Complete:    5,
+	}, v)
+
+	// default byte slice is not shared between values.
+	v.Key[0] = 'x'
+	var v2 announceResponse
+	require.NoError(t, bencode.Unmarshal([]byte("de"), &v2))
+	require.Equal(t, []byte("abc"), v2.Key)
+}
+
+func TestUnmarshal_default_invalid(t *testing.T) {
+	var v struct {
+		Interval int8 `bencode:"interval,default=1800"`
+	}
+
+	var unsupportedTypeError *bencode.UnsupportedTypeError
+	require.ErrorAs(t, bencode.Unmarshal([]byte("de"), &v), &unsupportedTypeError)
+
+	_, err := bencode.Marshal(v)
+	require.ErrorAs(t, err, &unsupportedTypeError)
+}
+
+func TestMarshalOptions_OmitDefaults(t *testing.T) {
+	v := announceResponse{
+		Interval:    1800,
+		MinInterval: 30,
+		Compact:     true,
+		TrackerID:   "none",
+		Key:         []byte("abc"),
+	}
+
+	actual, err := bencode.Marshal(v)
+	require.NoError(t, err)
+	require.Equal(t, "d7:compacti1e8:completei0e8:intervali1800e3:key3:abc12:min intervali30e10:tracker id4:nonee", string(actual))
+
+	actual, err = bencode.MarshalOptions{OmitDefaults: true}.Marshal(v)
+	require.NoError(t, err)
+	require.Equal(t, "d8:completei0e12:min intervali30ee", string(actual))
+
+	var decoded announceResponse
+	require.NoError(t, bencode.Unmarshal(actual, &decoded))
+	require.Equal(t, v, decoded)
+}
+
+type aliasedFile struct {
+	Path   []string `bencode:"path,alias=path.utf-8"`
+	Name   string   `bencode:"name,alias=name.utf-8,alias=n,default=unnamed"`
+	Length int64    `bencode:"length,required,alias=len"`
+}
+
+func TestUnmarshal_alias(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		expected aliasedFile
+	}{
+		{
+			name:     "primary",
+			raw:      "d6:lengthi1e4:name1:a4:pathl1:aee",
+			expected: aliasedFile{Path: []string{"a"}, Name: "a", Length: 1},
+		},
+		{
+			name:     "alias",
+			raw:      "d3:leni1e10:name.utf-81:b10:path.utf-8l1:b1:cee",
+			expected: aliasedFile{Path: []string{"b", "c"}, Name: "b", Length: 1},
+		},
+		{
+			name:     "primary before alias",
+			raw:      "d6:lengthi1e4:name1:a10:name.utf-81:b4:pathl1:ae10:path.utf-8l1:b1:cee",
+			expected: aliasedFile{Path: []string{"a"}, Name: "a", Length: 1},
+		},
+		{
+			name:     "alias before primary",
+			raw:      "d3:leni2e6:lengthi1e1:n1:c4:name1:a10:name.utf-81:be",
+			expected: aliasedFile{Name: "a", Length: 1},
+		},
+		{
+			name:     "second alias",
+			raw:      "d6:lengthi1e1:n1:c10:name.utf-81:be",
+			expected: aliasedFile{Name: "b", Length: 1},
+		},
+		{
+			name:     "default",
+			raw:      "d3:leni1ee",
+			expected: aliasedFile{Name: "unnamed", Length: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v aliasedFile
+			require.NoError(t, bencode.UnmarshalRelaxed([]byte(tt.raw), &v))
+			require.Equal(t, tt.expected, v)
+		})
+	}
+
+	var v aliasedFile
+	var missingFieldsError *bencode.MissingFieldsError
+	require.ErrorAs(t, bencode.Unmarshal([]byte("d4:name1:ae"), &v), &missingFieldsError)
+	require.Equal(t, []string{"length"}, missingFieldsError.Keys)
+}
+
+func TestUnmarshal_alias_embedded(t *testing.T) {
+	type Base struct {
+		Name string `bencode:"name,alias=name.utf-8"`
+	}
+
+	var v struct {
+		Base
+		Length int64 `bencode:"length"`
+	}
+
+	require.NoError(t, bencode.Unmarshal([]byte("d6:lengthi1e10:name.utf-81:be"), &v))
+	require.Equal(t, "b", v.Name)
+}
+
+func TestUnmarshal_alias_duplicate_key(t *testing.T) {
+	var v struct {
+		Name  string `bencode:"name,alias=title"`
+		Title string `bencode:"title"`
+	}
+
+	var unsupportedTypeError *bencode.UnsupportedTypeError
+	require.ErrorAs(t, bencode.Unmarshal([]byte("de"), &v), &unsupportedTypeError)
+}
+
+func TestMarshal_alias(t *testing.T) {
+	actual, err := bencode.Marshal(aliasedFile{Path: []string{"a"}, Name: "b", Length: 1})
+	require.NoError(t, err)
+	require.Equal(t, "d6:lengthi1e4:name1:b4:pathl1:aee", string(actual))
+}
+
+type inlineInfo struct {
+	Name        string                      `bencode:"name"`
+	PieceLength int64                       `bencode:"piece length"`
+	Extra       map[string]bencode.RawBytes `bencode:",inline"`
+}
+
+func TestUnmarshal_inline(t *testing.T) {
+	raw := "d5:filesld6:lengthi1e4:pathl1:aeee4:name1:x12:piece lengthi16384e6:pieces0:7:privatei1e6:source3:abce"
+
+	var v inlineInfo
+	require.NoError(t, bencode.Unmarshal([]byte(raw), &v))
+	require.Equal(t, inlineInfo{
+		Name:        "x",
+		PieceLength: 16384,
+		Extra: map[string]bencode.RawBytes{
+			"files":   bencode.RawBytes("ld6:lengthi1e4:pathl1:aeee"),
+			"pieces":  bencode.RawBytes("0:"),
+			"private": bencode.RawBytes("i1e"),
+			"source":  bencode.RawBytes("3:abc"),
+		},
+	}, v)
+
+	// round trip keeps all keys.
+	encoded, err := bencode.Marshal(v)
+	require.NoError(t, err)
+	require.Equal(t, raw, string(encoded))
+}
+
+func TestUnmarshal_inline_any(t *testing.T) {
+	var v struct {
+		Name  string         `bencode:"name"`
+		Extra map[string]any `bencode:",remain"`
+	}
+
+	require.NoError(t, bencode.Unmarshal([]byte("d1:ai1e4:name1:x1:zli2eee"), &v))
+	require.Equal(t, "x", v.Name)
+	require.Equal(t, map[string]any{"a": int64(1), "z": []any{int64(2)}}, v.Extra)
+
+	// inline field takes unknown keys instead of rejecting them.
+	require.NoError(t, bencode.UnmarshalOptions{DisallowUnknownFields: true}.Unmarshal([]byte("d1:bi1ee"), &v))
+	require.Equal(t, int64(1), v.Extra["b"])
+
+	var typeError *bencode.UnmarshalTypeError
+	var typed struct {
+		Extra map[string]int8 `bencode:",inline"`
+	}
+	require.ErrorAs(t, bencode.Unmarshal([]byte("d1:ai300ee"), &typed), &typeError)
+	require.Equal(t, "a", typeError.Path.String())
+}
+
+func TestMarshal_inline(t *testing.T) {
+	v := inlineInfo{Name: "x", PieceLength: 1}
+
+	encoded, err := bencode.Marshal(v)
+	require.NoError(t, err)
+	require.Equal(t, "d4:name1:x12:piece lengthi1ee", string(encoded))
+
+	v.Extra = map[string]bencode.RawBytes{"a": bencode.RawBytes("i1e"), "z": bencode.RawBytes("i2e")}
+	encoded, err = bencode.Marshal(v)
+	require.NoError(t, err)
+	require.Equal(t, "d1:ai1e4:name1:x12:piece lengthi1e1:zi2ee", string(encoded))
+
+	v.Extra["name"] = bencode.RawBytes("1:y")
+	_, err = bencode.Marshal(v)
+
+	var marshalError *bencode.MarshalError
+	require.ErrorAs(t, err, &marshalError)
+	require.Equal(t, "name", marshalError.Path.String())
+
+	var empty struct {
+		Extra map[string]any `bencode:",inline"`
+	}
+	empty.Extra = map[string]any{"b": 1, "a": "x"}
+	encoded, err = bencode.Marshal(empty)
+	require.NoError(t, err)
+	require.Equal(t, "d1:a1:x1:bi1ee", string(encoded))
+}
+
+func TestInline_invalid_type(t *testing.T) {
+	var v struct {
+		Extra []string `bencode:",inline"`
+	}
+
+	var unsupportedTypeError *bencode.UnsupportedTypeError
+	require.ErrorAs(t, bencode.Unmarshal([]byte("de"), &v), &unsupportedTypeError)
+
+	_, err := bencode.Marshal(v)
+	require.ErrorAs(t, err, &unsupportedTypeError)
+
+	var multiple struct {
+		A map[string]any `bencode:",inline"`
+		B map[string]any `bencode:",inline"`
+	}
+
+	require.ErrorAs(t, bencode.Unmarshal([]byte("de"), &multiple), &unsupportedTypeError)
+
+	_, err = bencode.Marshal(multiple)
+	require.ErrorAs(t, err, &unsupportedTypeError)
+}
+
+func TestInline_embedded(t *testing.T) {
+	type Base struct {
+		Extra map[string]any `bencode:",inline"`
+	}
+
+	var v struct {
+		Base
+		Name string `bencode:"name"`
+	}
+
+	require.NoError(t, bencode.Unmarshal([]byte("d1:ai1e4:name1:xe"), &v))
+	require.Equal(t, map[string]any{"a": int64(1)}, v.Extra)
+
+	encoded, err := bencode.Marshal(v)
+	require.NoError(t, err)
+	require.Equal(t, "d1:ai1e4:name1:xe", string(encoded))
+}
+
+type stringOption struct {
+	Int     int      `bencode:"int,string"`
+	Int8    int8     `bencode:"int8,string"`
+	Uint    uint64   `bencode:"uint,string"`
+	Bool    bool     `bencode:"bool,string"`
+	Big     big.Int  `bencode:"big,string"`
+	BigPtr  *big.Int `bencode:"big ptr,string"`
+	IntPtr  *int     `bencode:"int ptr,string"`
+	Str     string   `bencode:"str,string"`
+	Regular int      `bencode:"regular"`
+}
+
+func TestMarshal_string_option(t *testing.T) {
+	i := -3
+	v := stringOption{
+		Int:     -1,
+		Int8:    8,
+		Uint:    18446744073709551615,
+		Bool:    true,
+		Big:     *big.NewInt(12),
+		BigPtr:  big.NewInt(-34),
+		IntPtr:  &i,
+		Str:     "s",
+		Regular: 5,
+	}
+
+	actual, err := bencode.Marshal(v)
+	require.NoError(t, err)
+	require.Equal(t, "d3:big2:127:big ptr3:-344:bool1:13:int2:-17:int ptr2:-34:int81:87:regulari5e3:str1:s4:uint20:18446744073709551615e", string(actual))
+
+	var decoded stringOption
+	require.NoError(t, bencode.Unmarshal(actual, &decoded))
+	require.Equal(t, v, decoded)
+}
+
+func TestUnmarshal_string_option_from_integer(t *testing.T) {
+	var v stringOption
+	require.NoError(t, bencode.Unmarshal([]byte("d3:bigi12e7:big ptri-34e4:booli1e3:inti-1e7:int ptri-3e4:int8i8e4:uinti1ee"), &v))
+	require.Equal(t, -1, v.Int)
+	require.EqualValues(t, 8, v.Int8)
+	require.EqualValues(t, 1, v.Uint)
+	require.True(t, v.Bool)
+	require.Equal(t, "12", v.Big.String())
+	require.Equal(t, "-34", v.BigPtr.String())
+	require.Equal(t, -3, *v.IntPtr)
+}
+
+func TestUnmarshal_string_option_invalid(t *testing.T) {
+	for _, raw := range []string{
+		"d3:int1:xe",
+		"d4:int83:300e",
+		"d4:uint2:-1e",
+		"d4:bool3:yese",
+		"d3:big3:1.5e",
+	} {
+		t.Run(raw, func(t *testing.T) {
+			var v stringOption
+			var typeError *bencode.UnmarshalTypeError
+			require.ErrorAs(t, bencode.Unmarshal([]byte(raw), &v), &typeError)
+		})
+	}
+
+	var v stringOption
+	err := bencode.Unmarshal([]byte("d3:int1:xe"), &v)
+	require.Equal(t, `bencode: cannot unmarshal string "x" into Go value of type int at int (offset: 6)`, err.Error())
+}
+
+type infoHash [20]byte
+
+func (h infoHash) IsZero() bool {
+	return h == infoHash{}
+}
+
+type version struct {
+	Major, Minor int
+}
+
+// IsZero with pointer receiver, version{} is not zero.
+func (v *version) IsZero() bool {
+	return v.Major == -1
+}
+
+func TestMarshal_omitzero(t *testing.T) {
+	type Item struct {
+		Time    time.Time      `bencode:"time,omitzero"`
+		Hash    infoHash       `bencode:"hash,omitzero"`
+		Version version        `bencode:"version,omitzero"`
+		Count   int            `bencode:"count,omitzero"`
+		List    []int          `bencode:"list,omitzero"`
+		Map     map[string]int `bencode:"map,omitzero"`
+	}
+
+	actual, err := bencode.Marshal(Item{Version: version{Major: -1}})
+	require.NoError(t, err)
+	require.Equal(t, "de", string(actual))
+
+	// empty but not nil slice and map are not zero.
+	actual, err = bencode.Marshal(Item{Version: version{Major: -1}, List: []int{}, Map: map[string]int{}})
+	require.NoError(t, err)
+	require.Equal(t, "d4:listle3:mapdee", string(actual))
+
+	actual, err = bencode.Marshal(&Item{})
+	require.NoError(t, err)
+	require.Equal(t, "d7:versiond5:Majori0e5:Minori0eee", string(actual))
+
+	actual, err = bencode.Marshal(Item{Version: version{Major: -1}, Hash: infoHash{1}, Count: 2})
+	require.NoError(t, err)
+	require.Equal(t, "d5:counti2e4:hash20:\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00e", string(actual))
+}
+
+func TestMarshal_omitzero_pointer(t *testing.T) {
+	type Item struct {
+		Time *time.Time `bencode:"time,omitzero"`
+	}
+
+	var zero time.Time
+	actual, err := bencode.Marshal(Item{Time: &zero})
+	require.NoError(t, err)
+	require.Equal(t, "de", string(actual))
+
+	actual, err = bencode.Marshal(Item{})
+	require.NoError(t, err)
+	require.Equal(t, "de", string(actual))
+}
+
+func TestMarshal_omitzero_interface(t *testing.T) {
+	type zeroer interface {
+		IsZero() bool
+	}
+
+	type Item struct {
+		F zeroer `bencode:"f,omitzero"`
+	}
+
+	for _, v := range []zeroer{nil, (*version)(nil), infoHash{}, &version{Major: -1}} {
+		actual, err := bencode.Marshal(Item{F: v})
+		require.NoError(t, err)
+		require.Equal(t, "de", string(actual))
+	}
+
+	actual, err := bencode.Marshal(Item{F: &version{Major: 1}})
+	require.NoError(t, err)
+	require.Equal(t, "d1:fd5:Majori1e5:Minori0eee", string(actual))
+}
+
+func TestMarshal_omitempty_empty_values(t *testing.T) {
+	type Item struct {
+		Str   string         `bencode:"str,omitempty"`
+		List  []int          `bencode:"list,omitempty"`
+		Map   map[string]int `bencode:"map,omitempty"`
+		Count int            `bencode:"count,omitempty"`
+	}
+
+	actual, err := bencode.Marshal(Item{List: []int{}, Map: map[string]int{}})
+	require.NoError(t, err)
+	require.Equal(t, "de", string(actual))
+
+	actual, err = bencode.Marshal(Item{Str: "a", List: []int{1}})
+	require.NoError(t, err)
+	require.Equal(t, "d4:listli1ee3:str1:ae", string(actual))
+}
+
+type node struct {
+	_    struct{} `bencode:",tuple"`
+	Host string
+	Port uint16
+}
+
+type krpcError struct {
+	_       struct{} `bencode:",tuple"`
+	Code    int
+	Message string
+}
+
+func TestTuple(t *testing.T) {
+	var v struct {
+		Nodes []node    `bencode:"nodes"`
+		Error krpcError `bencode:"e"`
+	}
+
+	raw := "d1:eli201e13:Generic Errore5:nodesll9:127.0.0.1i6881eel3:::1i80eeee"
+
+	require.NoError(t, bencode.Unmarshal([]byte(raw), &v))
+	require.Equal(t, []node{{Host: "127.0.0.1", Port: 6881}, {Host: "::1", Port: 80}}, v.Nodes)
+	require.Equal(t, krpcError{Code: 201, Message: "Generic Error"}, v.Error)
+
+	encoded, err := bencode.Marshal(v)
+	require.NoError(t, err)
+	require.Equal(t, raw, string(encoded))
+}
+
+func TestTuple_field_options(t *testing.T) {
+	type Item struct {
+		_       struct{} `bencode:",tuple"`
+		ID      int      `bencode:",string"`
+		Ignored int      `bencode:"-"`
+		private int
+		Ptr     *int
+	}
+
+	i := 2
+	encoded, err := bencode.Marshal(Item{ID: 1, Ignored: 5, Ptr: &i})
+	require.NoError(t, err)
+	require.Equal(t, "l1:1i2ee", string(encoded))
+
+	var v Item
+	require.NoError(t, bencode.Unmarshal(encoded, &v))
+	require.Equal(t, 1, v.ID)
+	require.Equal(t, 2, *v.Ptr)
+
+	_, err = bencode.Marshal(Item{})
+	require.ErrorIs(t, err, bencode.ErrNilPtr)
+}
+
+func TestTuple_length_mismatch(t *testing.T) {
+	for _, raw := range []string{"le", "l1:ae", "l1:ai1ei2ee", "l1:ai1ei2ei3ee"} {
+		t.Run(raw, func(t *testing.T) {
+			var v node
+			var typeError *bencode.UnmarshalTypeError
+			require.ErrorAs(t, bencode.Unmarshal([]byte(raw), &v), &typeError)
+		})
+	}
+
+	var v node
+	err := bencode.Unmarshal([]byte("l1:ai1ei2ei3ee"), &v)
+	require.Equal(t, "bencode: cannot unmarshal list of length 4 into Go tuple struct bencode_test.node with 2 fields (offset 0)", err.Error())
+
+	var typeError *bencode.UnmarshalTypeError
+	require.ErrorAs(t, bencode.Unmarshal([]byte("d1:ai1ee"), &v), &typeError)
+
+	var syntaxError *bencode.SyntaxError
+	require.ErrorAs(t, bencode.Unmarshal([]byte("l1:ai1ei2e"), &v), &syntaxError)
+}
+
+func TestTuple_path(t *testing.T) {
+	var v []node
+	err := bencode.Unmarshal([]byte("ll1:ai1eel1:ai70000eee"), &v)
+
+	var typeError *bencode.UnmarshalTypeError
+	require.ErrorAs(t, err, &typeError)
+	require.Equal(t, "[1][1]", typeError.Path.String())
+}
+
+func TestTuple_embedded(t *testing.T) {
+	type Node struct {
+		_    struct{} `bencode:",tuple"`
+		Host string
+	}
+
+	var v struct {
+		Node
+	}
+
+	var unsupportedTypeError *bencode.UnsupportedTypeError
+	require.ErrorAs(t, bencode.Unmarshal([]byte("de"), &v), &unsupportedTypeError)
+
+	_, err := bencode.Marshal(v)
+	require.ErrorAs(t, err, &unsupportedTypeError)
+}
+
+func TestPacked(t *testing.T) {
+	type Info struct {
+		Pieces [][4]byte  `bencode:"pieces,packed"`
+		Ptr    *[][2]byte `bencode:"ptr,packed,omitempty"`
+	}
+
+	raw := "d6:pieces8:abcdefghe"
+
+	var v Info
+	require.NoError(t, bencode.Unmarshal([]byte(raw), &v))
+	require.Equal(t, [][4]byte{{'a', 'b', 'c', 'd'}, {'e', 'f', 'g', 'h'}}, v.Pieces)
+	require.Nil(t, v.Ptr)
+
+	encoded, err := bencode.Marshal(v)
+	require.NoError(t, err)
+	require.Equal(t, raw, string(encoded))
+
+	require.NoError(t, bencode.Unmarshal([]byte("d6:pieces0:3:ptr2:xye"), &v))
+	require.Empty(t, v.Pieces)
+	require.Equal(t, [][2]byte{{'x', 'y'}}, *v.Ptr)
+
+	encoded, err = bencode.Marshal(Info{})
+	require.NoError(t, err)
+	require.Equal(t, "d6:pieces0:e", string(encoded))
+}
+
+func TestPacked_tuple_pointer(t *testing.T) {
+	type Item struct {
+		_      struct{}   `bencode:",tuple"`
+		Pieces *[][2]byte `bencode:",packed"`
+		Name   string
+	}
+
+	pieces := [][2]byte{{'a', 'b'}, {'c', 'd'}}
+
+	encoded, err := bencode.Marshal(Item{Pieces: &pieces, Name: "x"})
+	require.NoError(t, err)
+	require.Equal(t, "l4:abcd1:xe", string(encoded))
+
+	var v Item
+	require.NoError(t, bencode.Unmarshal(encoded, &v))
+	require.Equal(t, pieces, *v.Pieces)
+	require.Equal(t, "x", v.Name)
+
+	encoded, err = bencode.Marshal(Item{Name: "x"})
+	require.NoError(t, err)
+	require.Equal(t, "l0:1:xe", string(encoded))
+}
+
+func TestPacked_invalid_length(t *testing.T) {
+	var v struct {
+		Pieces [][4]byte `bencode:"pieces,packed"`
+	}
+
+	err := bencode.Unmarshal([]byte("d6:pieces6:abcdefe"), &v)
+
+	var typeError *bencode.UnmarshalTypeError
+	require.ErrorAs(t, err, &typeError)
+	require.Equal(t, "string of length 6", typeError.Value)
+	require.Equal(t, 9, typeError.Offset)
+
+	require.ErrorAs(t, bencode.Unmarshal([]byte("d6:piecesli1eee"), &v), &typeError)
+}
+
+func TestPacked_invalid_type(t *testing.T) {
+	var v struct {
+		Pieces []byte `bencode:"pieces,packed"`
+	}
+
+	var unsupportedTypeError *bencode.UnsupportedTypeError
+	require.ErrorAs(t, bencode.Unmarshal([]byte("de"), &v), &unsupportedTypeError)
+
+	_, err := bencode.Marshal(v)
+	require.ErrorAs(t, err, &unsupportedTypeError)
+}
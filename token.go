@@ -0,0 +1,64 @@
+package bencode
+
+import (
+	"github.com/trim21/go-bencode/internal/decoder"
+)
+
+// TokenKind is the type of [Token].
+type TokenKind = decoder.TokenKind
+
+const (
+	DictStart = decoder.DictStart // 'd'
+	ListStart = decoder.ListStart // 'l'
+	End       = decoder.End       // 'e' of a list or dictionary
+	Int       = decoder.Int       // i${digits}e
+	String    = decoder.String    // ${length}:${content}, including dictionary keys
+)
+
+// Token is a single bencode token.
+//
+// Offset is the index of the token's first byte in the input.
+// For Int tokens, Bytes holds the raw digits, [Token.Int64] parse them.
+// For String tokens, Bytes holds the string content.
+// Bytes is a view into the input and is not copied.
+type Token = decoder.Token
+
+// TokenReader reads bencode input token by token, without building any Go value.
+//
+// It checks the same syntax rules as [Unmarshal]: integers and string lengths are validated,
+// dictionary keys must be strings, and in strict mode they must be sorted and unique.
+// After the top-level value, [TokenReader.Next] returns [io.EOF],
+// or an error if there is trailing data.
+type TokenReader struct {
+	r decoder.TokenReader
+}
+
+// NewTokenReader returns a TokenReader reading from data with strict key order rules.
+func NewTokenReader(data []byte) *TokenReader {
+	return &TokenReader{r: decoder.NewTokenReader(data, false)}
+}
+
+// NewTokenReaderRelaxed is like NewTokenReader but with relaxed key order rules like [UnmarshalRelaxed]:
+// - Dictionary keys are not required to be sorted
+// - Duplicate dictionary keys are allowed
+func NewTokenReaderRelaxed(data []byte) *TokenReader {
+	return &TokenReader{r: decoder.NewTokenReader(data, true)}
+}
+
+// Next returns the next token.
+// Once an error is returned, all following calls return the same error.
+func (r *TokenReader) Next() (Token, error) {
+	return r.r.Next()
+}
+
+// Peek returns the next token without consuming it.
+func (r *TokenReader) Peek() (Token, error) {
+	return r.r.Peek()
+}
+
+// Skip consumes the next value. If it's a list or dictionary, the whole container is skipped.
+//
+// Calling Skip after reading a dictionary key skips the value of that key.
+func (r *TokenReader) Skip() error {
+	return r.r.Skip()
+}
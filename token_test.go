@@ -0,0 +1,185 @@
+package bencode_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trim21/go-bencode"
+)
+
+func readAllTokens(t *testing.T, r *bencode.TokenReader) []bencode.Token {
+	t.Helper()
+
+	var tokens []bencode.Token
+	for {
+		tok, err := r.Next()
+		if err == io.EOF {
+			return tokens
+		}
+		require.NoError(t, err)
+		tokens = append(tokens, tok)
+	}
+}
+
+func TestTokenReader(t *testing.T) {
+	raw := []byte("d1:ali-1ei20ee1:b3:xyz1:cdee")
+
+	tokens := readAllTokens(t, bencode.NewTokenReader(raw))
+
+	require.Equal(t, []bencode.Token{
+		{Kind: bencode.DictStart, Offset: 0},
+		{Kind: bencode.String, Offset: 1, Bytes: []byte("a")},
+		{Kind: bencode.ListStart, Offset: 4},
+		{Kind: bencode.Int, Offset: 5, Bytes: []byte("-1")},
+		{Kind: bencode.Int, Offset: 9, Bytes: []byte("20")},
+		{Kind: bencode.End, Offset: 13},
+		{Kind: bencode.String, Offset: 14, Bytes: []byte("b")},
+		{Kind: bencode.String, Offset: 17, Bytes: []byte("xyz")},
+		{Kind: bencode.String, Offset: 22, Bytes: []byte("c")},
+		{Kind: bencode.DictStart, Offset: 25},
+		{Kind: bencode.End, Offset: 26},
+		{Kind: bencode.End, Offset: 27},
+	}, tokens)
+
+	i, err := tokens[3].Int64()
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), i)
+
+	_, err = tokens[1].Int64()
+	var typeError *bencode.UnmarshalTypeError
+	require.ErrorAs(t, err, &typeError)
+	require.Equal(t, 1, typeError.Offset)
+
+	_, err = bencode.Token{Kind: bencode.Int, Offset: 3, Bytes: []byte("99999999999999999999")}.Int64()
+	require.ErrorAs(t, err, &typeError)
+	require.Equal(t, 3, typeError.Offset)
+}
+
+func TestTokenReader_Peek(t *testing.T) {
+	r := bencode.NewTokenReader([]byte("li1ee"))
+
+	tok, err := r.Peek()
+	require.NoError(t, err)
+	require.Equal(t, bencode.ListStart, tok.Kind)
+
+	tok, err = r.Next()
+	require.NoError(t, err)
+	require.Equal(t, bencode.ListStart, tok.Kind)
+
+	tok, err = r.Peek()
+	require.NoError(t, err)
+	require.Equal(t, bencode.Int, tok.Kind)
+
+	tok, err = r.Next()
+	require.NoError(t, err)
+	require.Equal(t, bencode.Int, tok.Kind)
+}
+
+func TestTokenReader_Skip(t *testing.T) {
+	r := bencode.NewTokenReader([]byte("d1:ald1:xi1eee1:bi2ee"))
+
+	tok, err := r.Next()
+	require.NoError(t, err)
+	require.Equal(t, bencode.DictStart, tok.Kind)
+
+	tok, err = r.Next()
+	require.NoError(t, err)
+	require.Equal(t, "a", string(tok.Bytes))
+
+	require.NoError(t, r.Skip())
+
+	tok, err = r.Next()
+	require.NoError(t, err)
+	require.Equal(t, "b", string(tok.Bytes))
+
+	tok, err = r.Peek()
+	require.NoError(t, err)
+	require.Equal(t, bencode.Int, tok.Kind)
+	require.NoError(t, r.Skip())
+
+	tok, err = r.Next()
+	require.NoError(t, err)
+	require.Equal(t, bencode.End, tok.Kind)
+
+	_, err = r.Next()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestTokenReader_Skip_end(t *testing.T) {
+	r := bencode.NewTokenReader([]byte("le"))
+	_, err := r.Next()
+	require.NoError(t, err)
+	require.Error(t, r.Skip())
+}
+
+func TestTokenReader_Skip_error_path(t *testing.T) {
+	raw := []byte("ld1:ali1ei1xeeee")
+
+	r := bencode.NewTokenReader(raw)
+	err := r.Skip()
+
+	var syntaxError *bencode.SyntaxError
+	require.ErrorAs(t, err, &syntaxError)
+	require.Equal(t, "[0].a[1]", syntaxError.Path.String())
+
+	require.ErrorAs(t, bencode.Validate(raw), &syntaxError)
+	require.Equal(t, "[0].a[1]", syntaxError.Path.String())
+}
+
+func TestTokenReader_key_order(t *testing.T) {
+	for _, raw := range []string{"d1:bi1e1:ai2ee", "d1:ai1e1:ai2ee"} {
+		t.Run(raw, func(t *testing.T) {
+			r := bencode.NewTokenReader([]byte(raw))
+			var err error
+			for err == nil {
+				_, err = r.Next()
+			}
+			require.NotErrorIs(t, err, io.EOF)
+
+			require.Len(t, readAllTokens(t, bencode.NewTokenReaderRelaxed([]byte(raw))), 6)
+		})
+	}
+
+	t.Run("skipped container", func(t *testing.T) {
+		raw := []byte("ld1:bi1e1:ai2eee")
+
+		r := bencode.NewTokenReader(raw)
+		_, err := r.Next()
+		require.NoError(t, err)
+		require.Error(t, r.Skip())
+
+		r = bencode.NewTokenReaderRelaxed(raw)
+		_, err = r.Next()
+		require.NoError(t, err)
+		require.NoError(t, r.Skip())
+	})
+}
+
+func TestTokenReader_invalid(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"e",
+		"x",
+		"i01e",
+		"5:ab",
+		"d1:ae",
+		"di1ei2ee",
+		"l",
+		"i1ei2e",
+	} {
+		t.Run(raw, func(t *testing.T) {
+			r := bencode.NewTokenReader([]byte(raw))
+			var err error
+			for err == nil {
+				_, err = r.Next()
+			}
+			require.NotErrorIs(t, err, io.EOF)
+
+			// error is sticky
+			_, err2 := r.Next()
+			require.Equal(t, err, err2)
+		})
+	}
+}
@@ -1,30 +1,93 @@
 package bencode
 
 import (
-	"errors"
-
 	"github.com/trim21/go-bencode/internal/decoder"
+	"github.com/trim21/go-bencode/internal/errors"
 )
 
 type Unmarshaler interface {
 	UnmarshalBencode([]byte) error
 }
 
-func Unmarshal(data []byte, v any) error {
+// UnmarshalOptions configures decoding, its zero value decodes like [Unmarshal].
+type UnmarshalOptions struct {
+	// Relaxed enables the relaxed parsing rules of [UnmarshalRelaxed].
+	Relaxed bool
+
+	// LenientNumbers accepts integers written by broken encoders,
+	// like `i03e`, `i-0e`, `i+5e` or `i 5 e`, and string length with leading zeros like `03:abc`.
+	// It's independent of Relaxed.
+	LenientNumbers bool
+
+	// CollectErrors keeps decoding after a value that doesn't fit its Go type,
+	// see [Decoder.SetCollectErrors].
+	CollectErrors bool
+
+	// DisallowUnknownFields makes a dictionary key without matching struct field
+	// an [*UnknownFieldError], instead of skipping it.
+	// A single struct type can opt in with a blank field:
+	//
+	//	_ struct{} `bencode:",disallowunknown"`
+	DisallowUnknownFields bool
+
+	// Limits bounds the resources used to decode untrusted input.
+	Limits Limits
+}
+
+// Unmarshal decodes data into v with options o.
+func (o UnmarshalOptions) Unmarshal(data []byte, v any) error {
+	if len(data) == 0 {
+		return errors.ErrEmptyData()
+	}
+
+	return decoder.UnmarshalWithOptions(data, v, o.decoderOptions())
+}
+
+// UnmarshalPrefix is like [UnmarshalPrefix] with options o.
+func (o UnmarshalOptions) UnmarshalPrefix(data []byte, v any) (rest []byte, err error) {
 	if len(data) == 0 {
-		return errors.New("empty data")
+		return nil, errors.ErrEmptyData()
 	}
 
-	return decoder.Unmarshal(data, v)
+	n, err := decoder.UnmarshalPrefix(data, v, o.decoderOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	return data[n:], nil
+}
+
+func (o UnmarshalOptions) decoderOptions() decoder.Options {
+	return decoder.Options{
+		Relaxed:               o.Relaxed,
+		LenientNumbers:        o.LenientNumbers,
+		CollectErrors:         o.CollectErrors,
+		DisallowUnknownFields: o.DisallowUnknownFields,
+		Limits:                o.Limits,
+	}
+}
+
+func Unmarshal(data []byte, v any) error {
+	return UnmarshalOptions{}.Unmarshal(data, v)
 }
 
 // UnmarshalRelaxed is like Unmarshal but with relaxed parsing rules:
 // - Dictionary keys are not required to be sorted
 // - Duplicate dictionary keys are allowed (last value wins)
 func UnmarshalRelaxed(data []byte, v any) error {
-	if len(data) == 0 {
-		return errors.New("empty data")
-	}
+	return UnmarshalOptions{Relaxed: true}.Unmarshal(data, v)
+}
+
+// UnmarshalPrefix decodes the first bencode value of data into v,
+// and returns the data after it.
+//
+// It's useful for messages that have a bencode value followed by a raw payload,
+// like BEP 9 ut_metadata data message.
+func UnmarshalPrefix(data []byte, v any) (rest []byte, err error) {
+	return UnmarshalOptions{}.UnmarshalPrefix(data, v)
+}
 
-	return decoder.UnmarshalRelaxed(data, v)
+// UnmarshalPrefixRelaxed is like UnmarshalPrefix but with the relaxed parsing rules of [UnmarshalRelaxed].
+func UnmarshalPrefixRelaxed(data []byte, v any) (rest []byte, err error) {
+	return UnmarshalOptions{Relaxed: true}.UnmarshalPrefix(data, v)
 }
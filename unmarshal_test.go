@@ -908,6 +908,78 @@ func TestUnmarshalRelaxed_empty(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestUnmarshalPrefix(t *testing.T) {
+	t.Run("dict followed by payload", func(t *testing.T) {
+		var msg struct {
+			MsgType int `bencode:"msg_type"`
+			Piece   int `bencode:"piece"`
+		}
+
+		rest, err := bencode.UnmarshalPrefix([]byte("d8:msg_typei1e5:piecei0eeraw piece data"), &msg)
+		require.NoError(t, err)
+		require.Equal(t, 1, msg.MsgType)
+		require.Equal(t, "raw piece data", string(rest))
+	})
+
+	t.Run("no trailing data", func(t *testing.T) {
+		var v int
+		rest, err := bencode.UnmarshalPrefix([]byte("i1e"), &v)
+		require.NoError(t, err)
+		require.Equal(t, 1, v)
+		require.Empty(t, rest)
+	})
+
+	t.Run("concatenated values", func(t *testing.T) {
+		data := []byte("i1e1:ale")
+
+		var i int
+		data, err := bencode.UnmarshalPrefix(data, &i)
+		require.NoError(t, err)
+		var s string
+		data, err = bencode.UnmarshalPrefix(data, &s)
+		require.NoError(t, err)
+		var l []int
+		data, err = bencode.UnmarshalPrefix(data, &l)
+		require.NoError(t, err)
+
+		require.Equal(t, 1, i)
+		require.Equal(t, "a", s)
+		require.Empty(t, l)
+		require.Empty(t, data)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var v any
+		_, err := bencode.UnmarshalPrefix(nil, &v)
+		require.ErrorContains(t, err, "empty data")
+
+		_, err = bencode.UnmarshalPrefix([]byte("d1:ai1e"), &v)
+		require.Error(t, err)
+
+		_, err = bencode.UnmarshalPrefix([]byte("i1e"), v)
+		require.Error(t, err)
+	})
+
+	t.Run("relaxed", func(t *testing.T) {
+		raw := []byte("d1:bi2e1:ai1eetail")
+
+		var m map[string]int
+		_, err := bencode.UnmarshalPrefix(raw, &m)
+		require.Error(t, err)
+
+		rest, err := bencode.UnmarshalPrefixRelaxed(raw, &m)
+		require.NoError(t, err)
+		require.Equal(t, map[string]int{"a": 1, "b": 2}, m)
+		require.Equal(t, "tail", string(rest))
+
+		_, err = bencode.UnmarshalPrefixRelaxed(nil, &m)
+		require.ErrorContains(t, err, "empty data")
+
+		_, err = bencode.UnmarshalPrefixRelaxed([]byte("x"), &m)
+		require.Error(t, err)
+	})
+}
+
 // --- invalid type decoder (chan, func, float, complex) ---
 
 func TestUnmarshal_invalid_types(t *testing.T) {
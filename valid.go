@@ -0,0 +1,36 @@
+package bencode
+
+import (
+	"github.com/trim21/go-bencode/internal/decoder"
+	"github.com/trim21/go-bencode/internal/errors"
+)
+
+// Valid reports whether data is exactly one valid bencode value, following the strict rules of [Unmarshal].
+//
+// It only checks syntax and doesn't allocate any Go value.
+func Valid(data []byte) bool {
+	return Validate(data) == nil
+}
+
+// ValidRelaxed is like Valid but with the relaxed parsing rules of [UnmarshalRelaxed].
+func ValidRelaxed(data []byte) bool {
+	return ValidateRelaxed(data) == nil
+}
+
+// Validate is like Valid but returns the reason why data is invalid.
+func Validate(data []byte) error {
+	if len(data) == 0 {
+		return errors.ErrEmptyData()
+	}
+
+	return decoder.Validate(data, false)
+}
+
+// ValidateRelaxed is like Validate but with the relaxed parsing rules of [UnmarshalRelaxed].
+func ValidateRelaxed(data []byte) error {
+	if len(data) == 0 {
+		return errors.ErrEmptyData()
+	}
+
+	return decoder.Validate(data, true)
+}
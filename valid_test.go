@@ -0,0 +1,73 @@
+package bencode_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trim21/go-bencode"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		strict  bool
+		relaxed bool
+	}{
+		{name: "int", data: "i-10e", strict: true, relaxed: true},
+		{name: "string", data: "4:spam", strict: true, relaxed: true},
+		{name: "nested", data: "d1:ali1ed1:xleee1:b0:e", strict: true, relaxed: true},
+		{name: "unordered keys", data: "d1:bi1e1:ai2ee", strict: false, relaxed: true},
+		{name: "duplicate keys", data: "d1:ai1e1:ai2ee", strict: false, relaxed: true},
+		{name: "empty", data: ""},
+		{name: "trailing data", data: "i1ei2e"},
+		{name: "leading zero", data: "i01e"},
+		{name: "negative zero", data: "i-0e"},
+		{name: "truncated", data: "d1:a"},
+		{name: "non string key", data: "di1ei2ee"},
+		{name: "string too long", data: "10:abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := []byte(tt.data)
+
+			require.Equal(t, tt.strict, bencode.Valid(data))
+			require.Equal(t, tt.relaxed, bencode.ValidRelaxed(data))
+
+			if tt.strict {
+				require.NoError(t, bencode.Validate(data))
+			} else {
+				require.Error(t, bencode.Validate(data))
+			}
+
+			if tt.relaxed {
+				require.NoError(t, bencode.ValidateRelaxed(data))
+			} else {
+				require.Error(t, bencode.ValidateRelaxed(data))
+			}
+		})
+	}
+}
+
+func TestValid_no_alloc(t *testing.T) {
+	data, err := bencode.Marshal(map[string]any{
+		"announce": "udp://tracker.example.com:80/announce",
+		"info": map[string]any{
+			"length":       1024,
+			"name":         "file.bin",
+			"piece length": 16384,
+			"pieces":       [20]byte{},
+		},
+		"url-list": []string{"https://example.com/a", "https://example.com/b"},
+	})
+	require.NoError(t, err)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if !bencode.Valid(data) {
+			t.Fatal("data should be valid")
+		}
+	})
+	require.Zero(t, allocs)
+}
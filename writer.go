@@ -0,0 +1,231 @@
+package bencode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/trim21/go-bencode/internal/decoder"
+)
+
+var (
+	ErrUnsortedKey   = errors.New("bencode: dictionary keys are not sorted")
+	ErrDuplicateKey  = errors.New("bencode: duplicate dictionary key")
+	ErrMissingKey    = errors.New("bencode: dictionary value written without a key")
+	ErrMissingValue  = errors.New("bencode: missing value after dictionary key")
+	ErrUnexpectedKey = errors.New("bencode: key written outside of dictionary")
+	ErrUnbalancedEnd = errors.New("bencode: End without matching BeginDict or BeginList")
+)
+
+// writerFlushSize is how much data Writer buffers before writing to underlying io.Writer,
+// when a top-level value is not finished yet.
+const writerFlushSize = 32 * 1024
+
+type writerFrame struct {
+	dict    bool
+	hasKey  bool // a key is written and waiting for its value
+	keys    int
+	lastKey []byte
+}
+
+// Writer writes bencode token by token and makes sure the output is canonical.
+//
+// Dictionary keys must be written with [Writer.Key] in lexicographic order,
+// each followed by exactly one value. A call that would produce invalid output
+// returns an error and writes nothing, the Writer can still be used after that.
+//
+// Output is written to the underlying io.Writer when a top-level value is finished,
+// or when the buffered data becomes too large.
+type Writer struct {
+	w     io.Writer
+	buf   []byte
+	stack []writerFrame
+	err   error // sticky error from underlying io.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// BeginDict starts a dictionary, it must be closed with [Writer.End].
+func (w *Writer) BeginDict() error {
+	return w.begin(true)
+}
+
+// BeginList starts a list, it must be closed with [Writer.End].
+func (w *Writer) BeginList() error {
+	return w.begin(false)
+}
+
+// Key writes a dictionary key. Keys of a dictionary must be written in lexicographic order
+// and each key must be followed by its value.
+func (w *Writer) Key(key []byte) error {
+	if w.err != nil {
+		return w.err
+	}
+
+	if len(w.stack) == 0 || !w.stack[len(w.stack)-1].dict {
+		return fmt.Errorf("%w: %q", ErrUnexpectedKey, key)
+	}
+
+	f := &w.stack[len(w.stack)-1]
+	if f.hasKey {
+		return fmt.Errorf("%w: key %q is written before the value of key %q", ErrMissingValue, key, f.lastKey)
+	}
+
+	if f.keys != 0 {
+		switch bytes.Compare(f.lastKey, key) {
+		case 0:
+			return fmt.Errorf("%w: %q", ErrDuplicateKey, key)
+		case 1:
+			return fmt.Errorf("%w: %q after %q", ErrUnsortedKey, key, f.lastKey)
+		}
+	}
+
+	f.lastKey = append(f.lastKey[:0], key...)
+	f.hasKey = true
+	f.keys++
+	w.buf = AppendBytes(w.buf, key)
+
+	return nil
+}
+
+// Int writes an integer.
+func (w *Writer) Int(i int64) error {
+	if err := w.beforeValue(); err != nil {
+		return err
+	}
+
+	w.buf = AppendInt(w.buf, i)
+
+	return w.afterValue()
+}
+
+// BigInt writes an integer of arbitrary size, nil is written as 0.
+func (w *Writer) BigInt(i *big.Int) error {
+	if err := w.beforeValue(); err != nil {
+		return err
+	}
+
+	w.buf = append(w.buf, 'i')
+	if i == nil {
+		w.buf = append(w.buf, '0')
+	} else {
+		w.buf = i.Append(w.buf, 10)
+	}
+	w.buf = append(w.buf, 'e')
+
+	return w.afterValue()
+}
+
+// String writes a byte string.
+func (w *Writer) String(s []byte) error {
+	if err := w.beforeValue(); err != nil {
+		return err
+	}
+
+	w.buf = AppendBytes(w.buf, s)
+
+	return w.afterValue()
+}
+
+// Raw writes a pre-encoded bencode value.
+// raw must be exactly one valid value in canonical form.
+func (w *Writer) Raw(raw []byte) error {
+	if w.err != nil {
+		return w.err
+	}
+
+	if err := decoder.Validate(raw, false); err != nil {
+		return err
+	}
+
+	if err := w.beforeValue(); err != nil {
+		return err
+	}
+
+	w.buf = append(w.buf, raw...)
+
+	return w.afterValue()
+}
+
+// End closes the innermost dictionary or list.
+func (w *Writer) End() error {
+	if w.err != nil {
+		return w.err
+	}
+
+	if len(w.stack) == 0 {
+		return ErrUnbalancedEnd
+	}
+
+	f := &w.stack[len(w.stack)-1]
+	if f.dict && f.hasKey {
+		return fmt.Errorf("%w: %q", ErrMissingValue, f.lastKey)
+	}
+
+	w.stack = w.stack[:len(w.stack)-1]
+	w.buf = append(w.buf, 'e')
+
+	return w.afterValue()
+}
+
+func (w *Writer) begin(dict bool) error {
+	if err := w.beforeValue(); err != nil {
+		return err
+	}
+
+	if dict {
+		w.buf = append(w.buf, 'd')
+	} else {
+		w.buf = append(w.buf, 'l')
+	}
+
+	w.stack = append(w.stack, writerFrame{dict: dict})
+
+	return nil
+}
+
+// beforeValue checks a value can be written at current position.
+func (w *Writer) beforeValue() error {
+	if w.err != nil {
+		return w.err
+	}
+
+	if len(w.stack) == 0 {
+		return nil
+	}
+
+	f := &w.stack[len(w.stack)-1]
+	if f.dict {
+		if !f.hasKey {
+			return ErrMissingKey
+		}
+		f.hasKey = false
+	}
+
+	return nil
+}
+
+// afterValue flushes output if top-level value is finished or buffer is too large.
+func (w *Writer) afterValue() error {
+	if len(w.stack) == 0 || len(w.buf) >= writerFlushSize {
+		return w.flush()
+	}
+
+	return nil
+}
+
+func (w *Writer) flush() error {
+	n, err := w.w.Write(w.buf)
+	if err == nil && n != len(w.buf) {
+		err = io.ErrShortWrite
+	}
+
+	w.buf = w.buf[:0]
+	w.err = err
+
+	return err
+}
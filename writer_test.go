@@ -0,0 +1,151 @@
+package bencode_test
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trim21/go-bencode"
+	"github.com/trim21/go-bencode/internal/test"
+)
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := bencode.NewWriter(&buf)
+
+	big10, ok := new(big.Int).SetString("100000000000000000000", 10)
+	require.True(t, ok)
+
+	require.NoError(t, w.BeginDict())
+	require.NoError(t, w.Key([]byte("a")))
+	require.NoError(t, w.BeginList())
+	require.NoError(t, w.Int(-1))
+	require.NoError(t, w.BigInt(big10))
+	require.NoError(t, w.BigInt(nil))
+	require.NoError(t, w.End())
+	require.NoError(t, w.Key([]byte("b")))
+	require.NoError(t, w.String([]byte("xyz")))
+	require.NoError(t, w.Key([]byte("c")))
+	require.NoError(t, w.Raw([]byte("d1:ki1ee")))
+
+	require.Zero(t, buf.Len(), "output should be buffered until top-level value is finished")
+
+	require.NoError(t, w.End())
+
+	test.StringEqual(t, "d1:ali-1ei100000000000000000000ei0ee1:b3:xyz1:cd1:ki1eee", buf.String())
+}
+
+func TestWriter_top_level_values(t *testing.T) {
+	var buf bytes.Buffer
+	w := bencode.NewWriter(&buf)
+
+	require.NoError(t, w.Int(1))
+	require.Equal(t, "i1e", buf.String())
+
+	require.NoError(t, w.String([]byte("a")))
+	require.Equal(t, "i1e1:a", buf.String())
+}
+
+func TestWriter_errors(t *testing.T) {
+	t.Run("unsorted key", func(t *testing.T) {
+		w := bencode.NewWriter(&bytes.Buffer{})
+		require.NoError(t, w.BeginDict())
+		require.NoError(t, w.Key([]byte("b")))
+		require.NoError(t, w.Int(1))
+		require.ErrorIs(t, w.Key([]byte("a")), bencode.ErrUnsortedKey)
+	})
+
+	t.Run("duplicate key", func(t *testing.T) {
+		w := bencode.NewWriter(&bytes.Buffer{})
+		require.NoError(t, w.BeginDict())
+		require.NoError(t, w.Key([]byte("")))
+		require.NoError(t, w.Int(1))
+		require.ErrorIs(t, w.Key([]byte("")), bencode.ErrDuplicateKey)
+	})
+
+	t.Run("missing value at end", func(t *testing.T) {
+		w := bencode.NewWriter(&bytes.Buffer{})
+		require.NoError(t, w.BeginDict())
+		require.NoError(t, w.Key([]byte("a")))
+		require.ErrorIs(t, w.End(), bencode.ErrMissingValue)
+	})
+
+	t.Run("missing value before next key", func(t *testing.T) {
+		w := bencode.NewWriter(&bytes.Buffer{})
+		require.NoError(t, w.BeginDict())
+		require.NoError(t, w.Key([]byte("a")))
+		require.ErrorIs(t, w.Key([]byte("b")), bencode.ErrMissingValue)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		w := bencode.NewWriter(&bytes.Buffer{})
+		require.NoError(t, w.BeginDict())
+		require.ErrorIs(t, w.Int(1), bencode.ErrMissingKey)
+		require.ErrorIs(t, w.BeginList(), bencode.ErrMissingKey)
+	})
+
+	t.Run("key outside of dict", func(t *testing.T) {
+		w := bencode.NewWriter(&bytes.Buffer{})
+		require.ErrorIs(t, w.Key([]byte("a")), bencode.ErrUnexpectedKey)
+		require.NoError(t, w.BeginList())
+		require.ErrorIs(t, w.Key([]byte("a")), bencode.ErrUnexpectedKey)
+	})
+
+	t.Run("unbalanced end", func(t *testing.T) {
+		w := bencode.NewWriter(&bytes.Buffer{})
+		require.ErrorIs(t, w.End(), bencode.ErrUnbalancedEnd)
+		require.NoError(t, w.BeginList())
+		require.NoError(t, w.End())
+		require.ErrorIs(t, w.End(), bencode.ErrUnbalancedEnd)
+	})
+
+	t.Run("invalid raw", func(t *testing.T) {
+		w := bencode.NewWriter(&bytes.Buffer{})
+		require.Error(t, w.Raw([]byte("d1:bi1e1:ai2ee")))
+		require.Error(t, w.Raw([]byte("i1ei2e")))
+		require.Error(t, w.Raw(nil))
+	})
+
+	t.Run("rejected call writes nothing", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := bencode.NewWriter(&buf)
+		require.NoError(t, w.BeginDict())
+		require.NoError(t, w.Key([]byte("b")))
+		require.NoError(t, w.Int(1))
+		require.Error(t, w.Key([]byte("a")))
+		require.NoError(t, w.Key([]byte("c")))
+		require.NoError(t, w.Int(2))
+		require.NoError(t, w.End())
+		require.Equal(t, "d1:bi1e1:ci2ee", buf.String())
+	})
+}
+
+func TestWriter_write_error(t *testing.T) {
+	w := bencode.NewWriter(errWriter{})
+	require.Error(t, w.Int(1))
+	require.Error(t, w.Int(1), "write error is sticky")
+
+	w = bencode.NewWriter(shortWriter{})
+	require.Error(t, w.Int(1))
+}
+
+func TestWriter_flush_large_value(t *testing.T) {
+	var buf bytes.Buffer
+	w := bencode.NewWriter(&buf)
+
+	s := []byte(strings.Repeat("x", 1024))
+
+	require.NoError(t, w.BeginList())
+	for range 64 {
+		require.NoError(t, w.String(s))
+	}
+	require.NotZero(t, buf.Len(), "large value should be flushed before it's finished")
+	require.NoError(t, w.End())
+
+	var v [][]byte
+	require.NoError(t, bencode.Unmarshal(buf.Bytes(), &v))
+	require.Len(t, v, 64)
+}